@@ -0,0 +1,22 @@
+package config
+
+import "log"
+
+// AdminConfig holds the settings of the admin API
+type AdminConfig struct {
+	// Token must be sent as a bearer token; the admin API is disabled while
+	// it is empty
+	Token string
+}
+
+// Admin is the global admin API configuration
+var Admin = AdminConfig{}
+
+// InitAdmin loads admin API settings from environment variables
+func InitAdmin() {
+	Admin.Token = getEnvString("ADMIN_TOKEN", Admin.Token)
+
+	if Admin.Token != "" {
+		log.Println("🔑 Admin API enabled")
+	}
+}
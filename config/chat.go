@@ -0,0 +1,34 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// ChatConfig holds the settings of in-room text chat
+type ChatConfig struct {
+	Enabled     bool          // accept chat messages
+	HistorySize int           // messages kept per room and sent to joiners
+	HistoryTTL  time.Duration // lifetime of a room's history in Redis after its last message
+	MaxLength   int           // maximum length of a message in bytes
+}
+
+// Chat is the global chat configuration
+var Chat = ChatConfig{
+	Enabled:     true,
+	HistorySize: 50,
+	HistoryTTL:  24 * time.Hour,
+	MaxLength:   2000,
+}
+
+// InitChat loads chat settings from environment variables
+func InitChat() {
+	Chat.Enabled = getEnvBool("CHAT_ENABLED", Chat.Enabled)
+	Chat.HistorySize = getEnvInt("CHAT_HISTORY_SIZE", Chat.HistorySize)
+	Chat.HistoryTTL = getEnvDuration("CHAT_HISTORY_TTL", Chat.HistoryTTL)
+	Chat.MaxLength = getEnvInt("CHAT_MAX_LENGTH", Chat.MaxLength)
+
+	if Chat.Enabled {
+		log.Printf("💬 Chat enabled with %d messages of history per room", Chat.HistorySize)
+	}
+}
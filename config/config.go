@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-redis/redis/v8"
 	"github.com/joho/godotenv"
+	"github.com/rs/xid"
 )
 
 // Ctx is the shared context for Redis operations
@@ -15,6 +16,14 @@ var Ctx = context.Background()
 // Rdb is the global Redis client
 var Rdb *redis.Client
 
+// NodeID identifies this pod in the cluster; it changes on every start
+var NodeID = xid.New().String()
+
+// RedisEncoding is the encoding of messages published for other pods
+// ("binary" or "json"); messages in either encoding are accepted. It
+// defaults to json, which pods of older releases can still read.
+var RedisEncoding = "json"
+
 // InitEnv loads environment variables from .env file
 func InitEnv() {
 	if err := godotenv.Load(); err != nil {
@@ -24,6 +33,12 @@ func InitEnv() {
 
 // InitRedis initializes the Redis connection
 func InitRedis() {
+	RedisEncoding = getEnvString("REDIS_ENCODING", RedisEncoding)
+	if RedisEncoding != "binary" && RedisEncoding != "json" {
+		log.Printf("⚠️ Unknown REDIS_ENCODING %q, using json", RedisEncoding)
+		RedisEncoding = "json"
+	}
+
 	host := os.Getenv("REDIS_HOST")
 	if host == "" {
 		log.Println("ℹ️ Redis not configured; starting without clustering")
@@ -0,0 +1,29 @@
+package config
+
+import "log"
+
+// DatabaseConfig holds the settings of the SQL database storing persistent
+// rooms and the session log
+type DatabaseConfig struct {
+	// Driver is "sqlite" or "postgres"
+	Driver string
+	// URL is the SQLite file path or the PostgreSQL connection URL; the
+	// database is not used while it is empty
+	URL string
+}
+
+// Database is the global database configuration
+var Database = DatabaseConfig{
+	Driver: "sqlite",
+}
+
+// InitDatabase loads database settings from environment variables
+func InitDatabase() {
+	Database.Driver = getEnvString("DATABASE_DRIVER", Database.Driver)
+	Database.URL = getEnvString("DATABASE_URL", Database.URL)
+
+	if Database.Driver != "sqlite" && Database.Driver != "postgres" {
+		Database.Driver = "sqlite"
+		log.Printf("⚠️ DATABASE_DRIVER must be sqlite or postgres, using %v", Database.Driver)
+	}
+}
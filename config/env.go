@@ -0,0 +1,74 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// getEnvString returns the value of an environment variable or a default
+func getEnvString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// getEnvInt returns an integer environment variable or a default
+func getEnvInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("⚠️ Invalid value for %s: %q (using %d)", key, v, def)
+		return def
+	}
+	return n
+}
+
+// getEnvBool returns a boolean environment variable or a default
+func getEnvBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("⚠️ Invalid value for %s: %q (using %v)", key, v, def)
+		return def
+	}
+	return b
+}
+
+// getEnvDuration returns a duration environment variable (e.g. "30s") or a default
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("⚠️ Invalid value for %s: %q (using %v)", key, v, def)
+		return def
+	}
+	return d
+}
+
+// getEnvList returns a comma separated environment variable as a slice
+func getEnvList(key string, def []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
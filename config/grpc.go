@@ -0,0 +1,24 @@
+package config
+
+import "log"
+
+// GRPCConfig holds the settings of the gRPC signaling API
+type GRPCConfig struct {
+	Enabled bool // serve the gRPC API next to the HTTP server
+	Port    int  // TCP port of the gRPC server
+}
+
+// GRPC is the global gRPC configuration
+var GRPC = GRPCConfig{
+	Port: 5001,
+}
+
+// InitGRPC loads gRPC settings from environment variables
+func InitGRPC() {
+	GRPC.Enabled = getEnvBool("GRPC_ENABLED", GRPC.Enabled)
+	GRPC.Port = getEnvInt("GRPC_PORT", GRPC.Port)
+
+	if GRPC.Enabled {
+		log.Printf("📡 gRPC signaling API enabled on port %d", GRPC.Port)
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// HostConfig holds the settings of room host roles and the lobby of locked rooms
+type HostConfig struct {
+	// TokenSecret signs host tokens; claiming the host role with a token is
+	// disabled while it is empty
+	TokenSecret string
+	// LobbyTimeout is how long a client waits for admission to a locked room
+	LobbyTimeout time.Duration
+}
+
+// Host is the global host role configuration
+var Host = HostConfig{
+	LobbyTimeout: 5 * time.Minute,
+}
+
+// InitHost loads host role settings from environment variables
+func InitHost() {
+	Host.TokenSecret = getEnvString("HOST_TOKEN_SECRET", Host.TokenSecret)
+	Host.LobbyTimeout = getEnvDuration("LOBBY_TIMEOUT", Host.LobbyTimeout)
+
+	if Host.TokenSecret != "" {
+		log.Println("👑 Host tokens enabled")
+	}
+}
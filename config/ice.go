@@ -0,0 +1,44 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// ICEConfig holds the ICE servers advertised to clients
+type ICEConfig struct {
+	STUNURLs   []string      // e.g. stun:stun.l.google.com:19302
+	TURNURLs   []string      // e.g. turn:turn.example.com:3478?transport=udp
+	TURNSecret string        // shared secret of the TURN server (coturn use-auth-secret)
+	TURNTTL    time.Duration // lifetime of issued TURN credentials
+
+	EmbeddedSTUN bool   // run the built-in STUN binding server
+	STUNPort     int    // UDP port of the built-in STUN server
+	PublicHost   string // host advertised for embedded servers; defaults to the request host
+}
+
+// ICE is the global ICE server configuration
+var ICE = ICEConfig{
+	STUNURLs: []string{"stun:stun.l.google.com:19302"},
+	TURNTTL:  24 * time.Hour,
+	STUNPort: 3478,
+}
+
+// InitICE loads ICE server settings from environment variables
+func InitICE() {
+	ICE.STUNURLs = getEnvList("ICE_STUN_URLS", ICE.STUNURLs)
+	ICE.TURNURLs = getEnvList("ICE_TURN_URLS", ICE.TURNURLs)
+	ICE.TURNSecret = getEnvString("TURN_SECRET", ICE.TURNSecret)
+	ICE.TURNTTL = getEnvDuration("TURN_TTL", ICE.TURNTTL)
+	ICE.EmbeddedSTUN = getEnvBool("STUN_ENABLED", ICE.EmbeddedSTUN)
+	ICE.STUNPort = getEnvInt("STUN_PORT", ICE.STUNPort)
+	ICE.PublicHost = getEnvString("ICE_PUBLIC_HOST", ICE.PublicHost)
+
+	if len(ICE.TURNURLs) > 0 && ICE.TURNSecret == "" {
+		log.Println("⚠️ ICE_TURN_URLS set without TURN_SECRET; TURN servers will not be advertised")
+		return
+	}
+	if len(ICE.TURNURLs) > 0 {
+		log.Printf("🧊 Issuing TURN credentials for %v (ttl %v)", ICE.TURNURLs, ICE.TURNTTL)
+	}
+}
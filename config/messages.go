@@ -0,0 +1,36 @@
+package config
+
+import "log"
+
+// AppMessagesConfig holds the settings of application data messages, which
+// carry opaque JSON between room members
+type AppMessagesConfig struct {
+	Types       []string // message types clients may send, e.g. broadcast, direct
+	MaxDataSize int      // maximum size of the data of a message in bytes
+}
+
+// AppMessages is the global application message configuration. Every type is
+// disabled until listed in APP_MESSAGE_TYPES.
+var AppMessages = AppMessagesConfig{
+	MaxDataSize: 4096,
+}
+
+// InitAppMessages loads application message settings from environment variables
+func InitAppMessages() {
+	AppMessages.Types = getEnvList("APP_MESSAGE_TYPES", AppMessages.Types)
+	AppMessages.MaxDataSize = getEnvInt("APP_MESSAGE_MAX_SIZE", AppMessages.MaxDataSize)
+
+	if len(AppMessages.Types) > 0 {
+		log.Printf("✉️ Application messages enabled: %v (max %d bytes)", AppMessages.Types, AppMessages.MaxDataSize)
+	}
+}
+
+// Enabled reports whether clients may send messages of the given type
+func (c AppMessagesConfig) Enabled(msgType string) bool {
+	for _, t := range c.Types {
+		if t == msgType {
+			return true
+		}
+	}
+	return false
+}
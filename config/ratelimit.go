@@ -0,0 +1,107 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Rate describes a token bucket: PerSecond tokens are refilled every second
+// up to Burst tokens. A zero PerSecond disables the bucket.
+type Rate struct {
+	PerSecond float64
+	Burst     int
+}
+
+// RateLimitConfig holds the signaling rate limiting settings
+type RateLimitConfig struct {
+	Enabled             bool
+	Client              Rate            // per connection, all message types
+	IP                  Rate            // per source IP, all connections combined
+	ByType              map[string]Rate // per connection, per message type
+	MaxConnectionsPerIP int             // 0 means unlimited
+	MaxViolations       int             // rejected messages before disconnecting, 0 means never
+	TrustProxyHeaders   bool            // use Fly-Client-IP / X-Forwarded-For as the source IP
+}
+
+// RateLimit is the global rate limiting configuration
+var RateLimit = RateLimitConfig{
+	Enabled: true,
+	Client:  Rate{PerSecond: 20, Burst: 50},
+	IP:      Rate{PerSecond: 100, Burst: 200},
+	ByType: map[string]Rate{
+		"join":           {PerSecond: 0.5, Burst: 3},
+		"broadcast":      {PerSecond: 2, Burst: 10},
+		"chat":           {PerSecond: 2, Burst: 10},
+		"update-profile": {PerSecond: 0.5, Burst: 5},
+		"state-update":   {PerSecond: 5, Burst: 20},
+	},
+	MaxConnectionsPerIP: 20,
+	MaxViolations:       50,
+}
+
+// InitRateLimit loads rate limiting settings from environment variables
+func InitRateLimit() {
+	RateLimit.Enabled = getEnvBool("RATE_LIMIT_ENABLED", RateLimit.Enabled)
+	RateLimit.Client = getEnvRate("RATE_LIMIT_CLIENT", RateLimit.Client)
+	RateLimit.IP = getEnvRate("RATE_LIMIT_IP", RateLimit.IP)
+	RateLimit.MaxConnectionsPerIP = getEnvInt("RATE_LIMIT_MAX_CONNECTIONS_PER_IP", RateLimit.MaxConnectionsPerIP)
+	RateLimit.MaxViolations = getEnvInt("RATE_LIMIT_MAX_VIOLATIONS", RateLimit.MaxViolations)
+	RateLimit.TrustProxyHeaders = getEnvBool("TRUST_PROXY_HEADERS", RateLimit.TrustProxyHeaders)
+
+	// RATE_LIMIT_TYPES="join=0.5:3,offer=5:10"
+	if v := os.Getenv("RATE_LIMIT_TYPES"); v != "" {
+		for _, entry := range strings.Split(v, ",") {
+			msgType, spec, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok {
+				log.Printf("⚠️ Invalid RATE_LIMIT_TYPES entry: %q", entry)
+				continue
+			}
+			rate, err := parseRate(spec)
+			if err != nil {
+				log.Printf("⚠️ Invalid RATE_LIMIT_TYPES entry: %q", entry)
+				continue
+			}
+			RateLimit.ByType[msgType] = rate
+		}
+	}
+
+	if RateLimit.Enabled {
+		log.Printf("🚦 Rate limiting enabled (client %.1f/s, ip %.1f/s, max %d connections per IP)",
+			RateLimit.Client.PerSecond, RateLimit.IP.PerSecond, RateLimit.MaxConnectionsPerIP)
+	}
+}
+
+// getEnvRate parses a "rate:burst" environment variable
+func getEnvRate(key string, def Rate) Rate {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	rate, err := parseRate(v)
+	if err != nil {
+		log.Printf("⚠️ Invalid value for %s: %q (using default)", key, v)
+		return def
+	}
+	return rate
+}
+
+// parseRate parses "rate:burst" (e.g. "0.5:3"); burst defaults to the rate
+func parseRate(s string) (Rate, error) {
+	perSecond, burst, hasBurst := strings.Cut(s, ":")
+	r, err := strconv.ParseFloat(perSecond, 64)
+	if err != nil {
+		return Rate{}, err
+	}
+	b := int(r)
+	if hasBurst {
+		if b, err = strconv.Atoi(burst); err != nil {
+			return Rate{}, err
+		}
+	}
+	if b < 1 {
+		b = 1
+	}
+	return Rate{PerSecond: r, Burst: b}, nil
+}
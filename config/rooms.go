@@ -0,0 +1,46 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// RoomsConfig holds the settings of room expiry and idle clients
+type RoomsConfig struct {
+	// MaxDuration closes rooms that long after their first member joined;
+	// 0 is unlimited. Persistent rooms can override it.
+	MaxDuration time.Duration
+	// IdleTimeout disconnects room members that send nothing for that long;
+	// 0 disables it
+	IdleTimeout time.Duration
+	// ExpiryWarning is how long before a room closes its members receive
+	// room-expiring
+	ExpiryWarning time.Duration
+	// SweepInterval is how often expired rooms and idle clients are looked for
+	SweepInterval time.Duration
+}
+
+// Rooms is the global room expiry configuration
+var Rooms = RoomsConfig{
+	ExpiryWarning: time.Minute,
+	SweepInterval: 5 * time.Second,
+}
+
+// InitRooms loads room expiry settings from environment variables
+func InitRooms() {
+	Rooms.MaxDuration = getEnvDuration("ROOM_MAX_DURATION", Rooms.MaxDuration)
+	Rooms.IdleTimeout = getEnvDuration("CLIENT_IDLE_TIMEOUT", Rooms.IdleTimeout)
+	Rooms.ExpiryWarning = getEnvDuration("ROOM_EXPIRY_WARNING", Rooms.ExpiryWarning)
+	Rooms.SweepInterval = getEnvDuration("ROOM_SWEEP_INTERVAL", Rooms.SweepInterval)
+
+	if Rooms.SweepInterval <= 0 {
+		Rooms.SweepInterval = 5 * time.Second
+		log.Printf("⚠️ ROOM_SWEEP_INTERVAL must be positive, using %v", Rooms.SweepInterval)
+	}
+	if Rooms.MaxDuration > 0 {
+		log.Printf("⏱️ Rooms close after %v", Rooms.MaxDuration)
+	}
+	if Rooms.IdleTimeout > 0 {
+		log.Printf("⏱️ Idle clients are disconnected after %v", Rooms.IdleTimeout)
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"compress/flate"
+	"log"
+	"time"
+)
+
+// SignalingConfig holds the WebSocket signaling protocol settings
+type SignalingConfig struct {
+	MaxMessageSize int64         // maximum size of an incoming frame in bytes
+	PingInterval   time.Duration // interval between WebSocket ping frames
+	PongWait       time.Duration // time allowed without any frame from the peer
+	WriteWait      time.Duration // time allowed to write a frame to the peer
+	LegacyJSONPing bool          // also send the application-level {"type":"ping"} message
+	AnswerTimeout  time.Duration // time an HTTP session (WHIP/WHEP) waits for the peer's answer
+
+	Compression          bool // negotiate permessage-deflate (RFC 7692) with clients that offer it; off by default
+	CompressionLevel     int  // flate level from 1 (fastest) to 9 (smallest)
+	CompressionThreshold int  // messages smaller than this many bytes are sent uncompressed
+}
+
+// Signaling is the global signaling configuration
+var Signaling = SignalingConfig{
+	MaxMessageSize: 64 * 1024,
+	PingInterval:   30 * time.Second,
+	PongWait:       60 * time.Second,
+	WriteWait:      10 * time.Second,
+	AnswerTimeout:  10 * time.Second,
+
+	Compression:          false,
+	CompressionLevel:     flate.BestSpeed,
+	CompressionThreshold: 512,
+}
+
+// InitSignaling loads signaling protocol settings from environment variables
+func InitSignaling() {
+	Signaling.MaxMessageSize = int64(getEnvInt("MAX_MESSAGE_SIZE", int(Signaling.MaxMessageSize)))
+	Signaling.PingInterval = getEnvDuration("PING_INTERVAL", Signaling.PingInterval)
+	Signaling.PongWait = getEnvDuration("PONG_WAIT", Signaling.PongWait)
+	Signaling.WriteWait = getEnvDuration("WRITE_WAIT", Signaling.WriteWait)
+	Signaling.LegacyJSONPing = getEnvBool("LEGACY_JSON_PING", Signaling.LegacyJSONPing)
+	Signaling.AnswerTimeout = getEnvDuration("HTTP_ANSWER_TIMEOUT", Signaling.AnswerTimeout)
+	Signaling.Compression = getEnvBool("WS_COMPRESSION", Signaling.Compression)
+	Signaling.CompressionLevel = getEnvInt("WS_COMPRESSION_LEVEL", Signaling.CompressionLevel)
+	Signaling.CompressionThreshold = getEnvInt("WS_COMPRESSION_THRESHOLD", Signaling.CompressionThreshold)
+
+	// A pong must be able to arrive before the read deadline expires
+	if Signaling.PongWait <= Signaling.PingInterval {
+		Signaling.PongWait = Signaling.PingInterval * 2
+		log.Printf("⚠️ PONG_WAIT must exceed PING_INTERVAL, using %v", Signaling.PongWait)
+	}
+
+	if Signaling.CompressionLevel < flate.BestSpeed || Signaling.CompressionLevel > flate.BestCompression {
+		log.Printf("⚠️ WS_COMPRESSION_LEVEL must be between %d and %d, using %d", flate.BestSpeed, flate.BestCompression, flate.BestSpeed)
+		Signaling.CompressionLevel = flate.BestSpeed
+	}
+
+	log.Printf("📏 Maximum signaling message size: %d bytes", Signaling.MaxMessageSize)
+	log.Printf("💓 Ping every %v, peers time out after %v", Signaling.PingInterval, Signaling.PongWait)
+	if Signaling.Compression {
+		log.Printf("🗜️ WebSocket compression at level %d for messages of %d bytes or more", Signaling.CompressionLevel, Signaling.CompressionThreshold)
+	}
+}
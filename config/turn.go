@@ -0,0 +1,71 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+)
+
+// TURNServerConfig holds the settings of the embedded TURN relay
+type TURNServerConfig struct {
+	Enabled bool
+	Realm   string
+	Port    int    // UDP and TCP listener port
+	TLSPort int    // TLS listener port, used when TLSCert and TLSKey are set
+	TLSCert string // path to the PEM certificate
+	TLSKey  string // path to the PEM private key
+	RelayIP string // public IP advertised for relayed transport addresses
+	MinPort int    // first UDP port used for relays
+	MaxPort int    // last UDP port used for relays
+
+	MaxAllocationsPerUser int // concurrent allocations per client ID, 0 means unlimited
+	BandwidthLimit        int // bytes per second per allocation in each direction, 0 means unlimited
+}
+
+// TURN is the global embedded TURN server configuration
+var TURN = TURNServerConfig{
+	Realm:                 "gosignaling",
+	Port:                  3478,
+	TLSPort:               5349,
+	MinPort:               49152,
+	MaxPort:               65535,
+	MaxAllocationsPerUser: 5,
+	BandwidthLimit:        512 * 1024,
+}
+
+// InitTURN loads embedded TURN server settings from environment variables.
+// It must run after InitICE because the relay shares TURN_SECRET with the
+// credentials handed to clients.
+func InitTURN() {
+	TURN.Enabled = getEnvBool("TURN_ENABLED", TURN.Enabled)
+	TURN.Realm = getEnvString("TURN_REALM", TURN.Realm)
+	TURN.Port = getEnvInt("TURN_PORT", TURN.Port)
+	TURN.TLSPort = getEnvInt("TURN_TLS_PORT", TURN.TLSPort)
+	TURN.TLSCert = getEnvString("TURN_TLS_CERT", TURN.TLSCert)
+	TURN.TLSKey = getEnvString("TURN_TLS_KEY", TURN.TLSKey)
+	TURN.RelayIP = getEnvString("TURN_RELAY_IP", TURN.RelayIP)
+	TURN.MinPort = getEnvInt("TURN_RELAY_MIN_PORT", TURN.MinPort)
+	TURN.MaxPort = getEnvInt("TURN_RELAY_MAX_PORT", TURN.MaxPort)
+	TURN.MaxAllocationsPerUser = getEnvInt("TURN_MAX_ALLOCATIONS_PER_USER", TURN.MaxAllocationsPerUser)
+	TURN.BandwidthLimit = getEnvInt("TURN_BANDWIDTH_LIMIT", TURN.BandwidthLimit)
+
+	if !TURN.Enabled {
+		return
+	}
+
+	// Without a configured secret the relay only accepts credentials issued by this process
+	if ICE.TURNSecret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatalf("Failed to generate TURN secret: %v", err)
+		}
+		ICE.TURNSecret = hex.EncodeToString(secret)
+		log.Println("⚠️ TURN_SECRET not set; generated an ephemeral secret for the embedded TURN server")
+	}
+	log.Printf("🔁 Embedded TURN server enabled on port %d (relay ports %d-%d)", TURN.Port, TURN.MinPort, TURN.MaxPort)
+}
+
+// TLSEnabled reports whether the TURN server should accept TLS connections
+func (c TURNServerConfig) TLSEnabled() bool {
+	return c.TLSCert != "" && c.TLSKey != ""
+}
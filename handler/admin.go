@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/manager"
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+const maxAdminBodySize = 16 * 1024
+
+// RoomConfigPayload represents the settings of a persistent room sent to the admin API
+type RoomConfigPayload struct {
+	Capacity         int    `json:"capacity"`
+	Password         string `json:"password"`
+	Topology         string `json:"topology"`
+	RecordingAllowed bool   `json:"recording_allowed"`
+	// LifetimeSeconds closes the room for good that long after it was
+	// created, and ExpiresAt at a fixed time; without either it is kept
+	// until it is deleted
+	LifetimeSeconds int64      `json:"lifetime_seconds"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	// MaxDurationSeconds closes each meeting in the room that long after it
+	// started; 0 uses ROOM_MAX_DURATION
+	MaxDurationSeconds int64 `json:"max_duration_seconds"`
+}
+
+// persistentRoomInfo describes a persistent room in admin API responses
+type persistentRoomInfo struct {
+	RoomID            string     `json:"room_id"`
+	Capacity          int        `json:"capacity"`
+	PasswordProtected bool       `json:"password_protected"`
+	Topology          string     `json:"topology"`
+	RecordingAllowed  bool       `json:"recording_allowed"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	MaxDuration       int64      `json:"max_duration_seconds,omitempty"`
+	// ClientIDs are the members on the pod that answered
+	ClientIDs []string `json:"client_ids"`
+}
+
+// HandleAdminRooms serves the admin API for persistent rooms:
+// GET /admin/rooms, and GET, PUT and DELETE /admin/rooms/{room}
+func (h *Handler) HandleAdminRooms(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+
+	roomID := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/admin/rooms"), "/")
+	if roomID == "" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.listPersistentRooms(w)
+		return
+	}
+	if err := validateJoinRoom(&JoinRoomPayload{RoomID: roomID}); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		room, err := h.manager.GetPersistentRoom(roomID)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, toPersistentRoomInfo(room))
+	case http.MethodPut:
+		h.configureRoom(w, r, roomID)
+	case http.MethodDelete:
+		if err := h.manager.RemoveRoomConfig(roomID); err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) listPersistentRooms(w http.ResponseWriter) {
+	rooms, err := h.manager.GetPersistentRooms()
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	infos := make([]persistentRoomInfo, 0, len(rooms))
+	for _, room := range rooms {
+		infos = append(infos, toPersistentRoomInfo(room))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"rooms": infos})
+}
+
+// configureRoom creates a persistent room or replaces its settings
+func (h *Handler) configureRoom(w http.ResponseWriter, r *http.Request, roomID string) {
+	var p RoomConfigPayload
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxAdminBodySize)).Decode(&p); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	if p.Topology == "" {
+		p.Topology = model.TopologyMesh
+	}
+	if err := validateRoomConfig(&p); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	settings := manager.RoomSettings{
+		Capacity:         p.Capacity,
+		Password:         p.Password,
+		Topology:         p.Topology,
+		RecordingAllowed: p.RecordingAllowed,
+		Lifetime:         time.Duration(p.LifetimeSeconds) * time.Second,
+		MaxDuration:      time.Duration(p.MaxDurationSeconds) * time.Second,
+	}
+	if p.ExpiresAt != nil {
+		settings.ExpiresAt = *p.ExpiresAt
+	}
+	room, created, err := h.manager.ConfigureRoom(roomID, settings)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, toPersistentRoomInfo(room))
+}
+
+func toPersistentRoomInfo(room *model.Room) persistentRoomInfo {
+	info := persistentRoomInfo{
+		RoomID:            room.ID,
+		Capacity:          room.Config.Capacity,
+		PasswordProtected: room.Config.PasswordHash != "",
+		Topology:          room.Config.Topology,
+		RecordingAllowed:  room.Config.RecordingAllowed,
+		CreatedAt:         room.Config.CreatedAt,
+		MaxDuration:       int64(room.Config.MaxDuration.Seconds()),
+		ClientIDs:         make([]string, 0, len(room.Clients)),
+	}
+	if !room.Config.ExpiresAt.IsZero() {
+		expiresAt := room.Config.ExpiresAt
+		info.ExpiresAt = &expiresAt
+	}
+	for id := range room.Clients {
+		info.ClientIDs = append(info.ClientIDs, id)
+	}
+	sort.Strings(info.ClientIDs)
+	return info
+}
+
+// HandleAdminMetrics serves the expvar metrics on GET /admin/metrics
+func (h *Handler) HandleAdminMetrics(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	expvar.Handler().ServeHTTP(w, r)
+}
+
+// HandleAdminHistory serves the session log through the admin API:
+// GET /admin/history/rooms and GET /admin/history/sessions, filtered by the
+// room_id, client_id, from, to and limit query parameters
+func (h *Handler) HandleAdminHistory(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	f, err := parseHistoryFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/admin/history/rooms":
+		rooms, err := h.manager.RoomHistory(f)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"rooms": rooms})
+	case "/admin/history/sessions":
+		sessions, err := h.manager.SessionHistory(f)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"sessions": sessions})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// parseHistoryFilter reads a session log filter from query parameters;
+// times are RFC 3339
+func parseHistoryFilter(q url.Values) (repository.HistoryFilter, error) {
+	f := repository.HistoryFilter{
+		RoomID:   q.Get("room_id"),
+		ClientID: q.Get("client_id"),
+	}
+	for _, p := range []struct {
+		name string
+		t    *time.Time
+	}{{"from", &f.From}, {"to", &f.To}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return f, fmt.Errorf("%s must be an RFC 3339 time", p.name)
+			}
+			*p.t = t
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return f, errors.New("limit must be a positive number")
+		}
+		f.Limit = limit
+	}
+	return f, nil
+}
+
+// authorizeAdmin checks the admin token of a request and answers it when
+// the token is wrong or the admin API is disabled
+func authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if config.Admin.Token == "" {
+		http.NotFound(w, r)
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(bearerToken(r)), []byte(config.Admin.Token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+func writeAdminError(w http.ResponseWriter, err error) {
+	switch err {
+	case repository.ErrNotFound:
+		http.Error(w, "room not found", http.StatusNotFound)
+		return
+	case manager.ErrNoSessionLog:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	log.Printf("Admin API request failed: %v", err)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
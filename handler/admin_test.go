@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gosignaling/config"
+)
+
+func TestAdminMetrics(t *testing.T) {
+	saved := config.Admin
+	t.Cleanup(func() { config.Admin = saved })
+	h := newTestHandler()
+
+	get := func(token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/admin/metrics", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		h.HandleAdminMetrics(w, r)
+		return w
+	}
+
+	config.Admin.Token = ""
+	if w := get("secret"); w.Code != http.StatusNotFound {
+		t.Errorf("admin API disabled: got %d, want 404", w.Code)
+	}
+	config.Admin.Token = "secret"
+	if w := get(""); w.Code != http.StatusUnauthorized {
+		t.Errorf("no token: got %d, want 401", w.Code)
+	}
+	w := get("secret")
+	if w.Code != http.StatusOK {
+		t.Fatalf("admin token: got %d, want 200", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"ws_compressed_messages"`) {
+		t.Errorf("metrics missing from %s", w.Body)
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+
+	"gosignaling/config"
+	"gosignaling/model"
+)
+
+// ChatPayload represents the payload for posting a chat message
+type ChatPayload struct {
+	Text string `json:"text"`
+}
+
+// ChatEditPayload represents the payload for editing a chat message
+type ChatEditPayload struct {
+	MessageID string `json:"message_id"`
+	Text      string `json:"text"`
+}
+
+// ChatDeletePayload represents the payload for deleting a chat message
+type ChatDeletePayload struct {
+	MessageID string `json:"message_id"`
+}
+
+func (h *Handler) handleChat(c *model.Client, payload json.RawMessage) *model.Message {
+	if !config.Chat.Enabled {
+		return newErrorMessage("message type disabled", nil)
+	}
+	var chatPayload ChatPayload
+	if err := json.Unmarshal(payload, &chatPayload); err != nil {
+		log.Printf("Failed to unmarshal chat payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateChatText(chatPayload.Text); err != nil {
+		log.Printf("Rejected chat payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	if _, err := h.manager.SendChat(c, chatPayload.Text); err != nil {
+		log.Printf("Failed to send chat message: %v", err)
+		return newErrorMessage("failed to send chat message", nil)
+	}
+
+	return nil
+}
+
+func (h *Handler) handleChatEdit(c *model.Client, payload json.RawMessage) *model.Message {
+	if !config.Chat.Enabled {
+		return newErrorMessage("message type disabled", nil)
+	}
+	var editPayload ChatEditPayload
+	if err := json.Unmarshal(payload, &editPayload); err != nil {
+		log.Printf("Failed to unmarshal chat edit payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateMessageID(editPayload.MessageID); err != nil {
+		log.Printf("Rejected chat edit payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateChatText(editPayload.Text); err != nil {
+		log.Printf("Rejected chat edit payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	if _, err := h.manager.EditChat(c, editPayload.MessageID, editPayload.Text); err != nil {
+		log.Printf("Failed to edit chat message: %v", err)
+		return newErrorMessage("failed to edit chat message", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) handleChatDelete(c *model.Client, payload json.RawMessage) *model.Message {
+	if !config.Chat.Enabled {
+		return newErrorMessage("message type disabled", nil)
+	}
+	var deletePayload ChatDeletePayload
+	if err := json.Unmarshal(payload, &deletePayload); err != nil {
+		log.Printf("Failed to unmarshal chat delete payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateMessageID(deletePayload.MessageID); err != nil {
+		log.Printf("Rejected chat delete payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	if err := h.manager.DeleteChat(c, deletePayload.MessageID); err != nil {
+		log.Printf("Failed to delete chat message: %v", err)
+		return newErrorMessage("failed to delete chat message", err)
+	}
+
+	return nil
+}
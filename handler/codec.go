@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"gosignaling/model"
+	"gosignaling/signalingpb"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// WebSocket subprotocols selecting the wire encoding. Clients that request
+// none get JSON, as before subprotocols were negotiated.
+const (
+	subprotocolJSON     = "json"
+	subprotocolMsgpack  = "msgpack"
+	subprotocolProtobuf = "protobuf"
+)
+
+// wireCodec converts between messages and the frames of one subprotocol
+type wireCodec interface {
+	// frameType is the WebSocket frame type used for messages
+	frameType() int
+	encode(msg *model.Message) ([]byte, error)
+	// decode returns the JSON form of a client message, as accepted by processMessage
+	decode(data []byte) ([]byte, error)
+}
+
+// codecFor returns the codec of a negotiated subprotocol
+func codecFor(subprotocol string) wireCodec {
+	switch subprotocol {
+	case subprotocolMsgpack:
+		return msgpackCodec{}
+	case subprotocolProtobuf:
+		return protobufCodec{}
+	default:
+		return jsonCodec{}
+	}
+}
+
+// jsonCodec sends messages as JSON text frames
+type jsonCodec struct{}
+
+func (jsonCodec) frameType() int { return websocket.TextMessage }
+
+func (jsonCodec) encode(msg *model.Message) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (jsonCodec) decode(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// msgpackCodec sends messages as MessagePack maps with the same keys as JSON
+type msgpackCodec struct{}
+
+type msgpackMessage struct {
+	Type    string      `msgpack:"type"`
+	Payload interface{} `msgpack:"payload"`
+}
+
+func (msgpackCodec) frameType() int { return websocket.BinaryMessage }
+
+func (msgpackCodec) encode(msg *model.Message) ([]byte, error) {
+	var payload interface{}
+	if len(msg.Payload) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(msg.Payload))
+		dec.UseNumber()
+		if err := dec.Decode(&payload); err != nil {
+			return nil, err
+		}
+	}
+	return msgpack.Marshal(msgpackMessage{
+		Type:    string(msg.Type),
+		Payload: fromJSONNumbers(payload),
+	})
+}
+
+func (msgpackCodec) decode(data []byte) ([]byte, error) {
+	var msg msgpackMessage
+	if err := msgpack.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(ReceiveMessage{Type: msg.Type, Payload: payload})
+}
+
+// fromJSONNumbers replaces json.Number values so that integers are packed as integers
+func fromJSONNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = fromJSONNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = fromJSONNumbers(item)
+		}
+	}
+	return v
+}
+
+// protobufCodec sends the ServerMessage and ClientMessage types of the gRPC API
+type protobufCodec struct{}
+
+func (protobufCodec) frameType() int { return websocket.BinaryMessage }
+
+func (protobufCodec) encode(msg *model.Message) ([]byte, error) {
+	out, err := toServerMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(out)
+}
+
+func (protobufCodec) decode(data []byte) ([]byte, error) {
+	var in signalingpb.ClientMessage
+	if err := proto.Unmarshal(data, &in); err != nil {
+		return nil, err
+	}
+	return fromClientMessage(&in)
+}
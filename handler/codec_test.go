@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gosignaling/model"
+	"gosignaling/signalingpb"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// testSDP is an offer with one audio and one video section, about the size
+// browsers send
+var testSDP = strings.Join([]string{
+	"v=0",
+	"o=- 4611731400430051336 2 IN IP4 127.0.0.1",
+	"s=-",
+	"t=0 0",
+	"a=group:BUNDLE 0 1",
+	"a=extmap-allow-mixed",
+	"a=msid-semantic: WMS stream",
+	"m=audio 9 UDP/TLS/RTP/SAVPF 111 63 9 0 8 13 110 126",
+	"c=IN IP4 0.0.0.0",
+	"a=rtcp:9 IN IP4 0.0.0.0",
+	"a=ice-ufrag:Vx3G",
+	"a=ice-pwd:9Jv3sVmGLbkbq4nBXhPQqjbo",
+	"a=ice-options:trickle",
+	"a=fingerprint:sha-256 7B:8B:F0:65:5F:78:E2:51:3B:AC:6F:F3:3F:46:1B:35:DC:B8:5F:64:1A:24:C2:43:F0:A1:58:D0:A1:2C:19:08",
+	"a=setup:actpass",
+	"a=mid:0",
+	"a=extmap:1 urn:ietf:params:rtp-hdrext:ssrc-audio-level",
+	"a=extmap:2 http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time",
+	"a=sendrecv",
+	"a=msid:stream audio",
+	"a=rtcp-mux",
+	"a=rtpmap:111 opus/48000/2",
+	"a=rtcp-fb:111 transport-cc",
+	"a=fmtp:111 minptime=10;useinbandfec=1",
+	"a=ssrc:3735928559 cname:4TOk42mSjXCkVIa6",
+	"m=video 9 UDP/TLS/RTP/SAVPF 96 97 98 99 100 101",
+	"c=IN IP4 0.0.0.0",
+	"a=rtcp:9 IN IP4 0.0.0.0",
+	"a=ice-ufrag:Vx3G",
+	"a=ice-pwd:9Jv3sVmGLbkbq4nBXhPQqjbo",
+	"a=ice-options:trickle",
+	"a=fingerprint:sha-256 7B:8B:F0:65:5F:78:E2:51:3B:AC:6F:F3:3F:46:1B:35:DC:B8:5F:64:1A:24:C2:43:F0:A1:58:D0:A1:2C:19:08",
+	"a=setup:actpass",
+	"a=mid:1",
+	"a=sendrecv",
+	"a=msid:stream video",
+	"a=rtcp-mux",
+	"a=rtcp-rsize",
+	"a=rtpmap:96 VP8/90000",
+	"a=rtcp-fb:96 goog-remb",
+	"a=rtcp-fb:96 transport-cc",
+	"a=rtcp-fb:96 ccm fir",
+	"a=rtcp-fb:96 nack",
+	"a=rtcp-fb:96 nack pli",
+	"a=rtpmap:97 rtx/90000",
+	"a=fmtp:97 apt=96",
+	"a=rtpmap:98 VP9/90000",
+	"a=fmtp:98 profile-id=0",
+	"a=rtpmap:99 rtx/90000",
+	"a=fmtp:99 apt=98",
+	"a=rtpmap:100 H264/90000",
+	"a=fmtp:100 level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42e01f",
+	"a=rtpmap:101 rtx/90000",
+	"a=fmtp:101 apt=100",
+	"a=ssrc-group:FID 2882400001 2882400002",
+	"a=ssrc:2882400001 cname:4TOk42mSjXCkVIa6",
+	"a=ssrc:2882400002 cname:4TOk42mSjXCkVIa6",
+	"",
+}, "\r\n")
+
+const testClientID = "d0qkvb4h7ojs47rulv50"
+
+// testOffer is the offer message a client receives
+func testOffer() *model.Message {
+	payload, _ := json.Marshal(SDPOfferPayload{SDP: testSDP, ClientID: testClientID})
+	return &model.Message{Type: model.MessageTypeSDPOffer, Payload: payload}
+}
+
+// testClientOffers returns the offer a client sends, in each subprotocol
+func testClientOffers(t testing.TB) map[string][]byte {
+	payload, _ := json.Marshal(SDPOfferPayload{SDP: testSDP, ClientID: testClientID})
+	jsonFrame, _ := json.Marshal(ReceiveMessage{Type: "offer", Payload: payload})
+	msgpackFrame, err := msgpack.Marshal(msgpackMessage{
+		Type:    "offer",
+		Payload: map[string]interface{}{"sdp": testSDP, "client_id": testClientID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	protobufFrame, err := proto.Marshal(&signalingpb.ClientMessage{
+		Message: &signalingpb.ClientMessage_Offer{Offer: &signalingpb.SessionDescription{ClientId: testClientID, Sdp: testSDP}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string][]byte{
+		subprotocolJSON:     jsonFrame,
+		subprotocolMsgpack:  msgpackFrame,
+		subprotocolProtobuf: protobufFrame,
+	}
+}
+
+var subprotocols = []string{subprotocolJSON, subprotocolMsgpack, subprotocolProtobuf}
+
+// TestCodecsDecodeOffer checks that every subprotocol yields the same offer
+func TestCodecsDecodeOffer(t *testing.T) {
+	for name, frame := range testClientOffers(t) {
+		data, err := codecFor(name).decode(frame)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var msg ReceiveMessage
+		var offer SDPOfferPayload
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := json.Unmarshal(msg.Payload, &offer); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if msg.Type != "offer" || offer.SDP != testSDP || offer.ClientID != testClientID {
+			t.Errorf("%s: decoded %s", name, data)
+		}
+	}
+}
+
+func BenchmarkCodecEncode(b *testing.B) {
+	msg := testOffer()
+	for _, name := range subprotocols {
+		b.Run(name, func(b *testing.B) {
+			codec := codecFor(name)
+			data, _ := codec.encode(msg)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.encode(msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/msg")
+		})
+	}
+}
+
+// BenchmarkCodecDecode includes parsing the decoded JSON, as processMessage
+// does; the JSON codec passes frames through unchanged
+func BenchmarkCodecDecode(b *testing.B) {
+	frames := testClientOffers(b)
+	for _, name := range subprotocols {
+		b.Run(name, func(b *testing.B) {
+			codec, frame := codecFor(name), frames[name]
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				data, err := codec.decode(frame)
+				if err != nil {
+					b.Fatal(err)
+				}
+				var msg ReceiveMessage
+				if err := json.Unmarshal(data, &msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(frame)), "bytes/msg")
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"expvar"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+)
+
+// Compression metrics, published on /admin/metrics
+var (
+	wsCompressedMessages = expvar.NewInt("ws_compressed_messages")
+	wsCompressionInput   = expvar.NewInt("ws_compression_bytes_in")  // message bytes before compression
+	wsCompressionOutput  = expvar.NewInt("ws_compression_bytes_out") // frame bytes written to the network
+	wsCompressionSaved   = expvar.NewInt("ws_compression_bytes_saved")
+)
+
+var errHijackUnsupported = errors.New("response does not implement http.Hijacker")
+
+// countingConn counts the bytes written to a client. gorilla/websocket does not
+// report the compressed size of a message, so it is measured on the wire.
+type countingConn struct {
+	net.Conn
+	written atomic.Int64
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+// countingResponseWriter hands the upgrader a countingConn when it hijacks the connection
+type countingResponseWriter struct {
+	http.ResponseWriter
+	conn *countingConn
+}
+
+func (w *countingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	conn, brw, err := h.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	w.conn = &countingConn{Conn: conn}
+	return w.conn, brw, nil
+}
+
+// offersDeflate reports whether the client offered permessage-deflate, which
+// the upgrader accepts whenever compression is enabled
+func offersDeflate(r *http.Request) bool {
+	for _, header := range r.Header.Values("Sec-WebSocket-Extensions") {
+		for _, ext := range strings.Split(header, ",") {
+			name, _, _ := strings.Cut(ext, ";")
+			if strings.TrimSpace(name) == "permessage-deflate" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readMessage reads the next message like ReadMessage. The read limit set on
+// the connection only bounds compressed frames, so the limit is applied again
+// after decompression.
+func readMessage(ws *websocket.Conn, limit int64) ([]byte, error) {
+	_, r, err := ws.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, websocket.ErrReadLimit
+	}
+	return data, nil
+}
+
+// recordCompression updates the metrics for a compressed message of size
+// bytes that took written bytes on the wire
+func recordCompression(size int, written int64) {
+	wsCompressedMessages.Add(1)
+	wsCompressionInput.Add(int64(size))
+	wsCompressionOutput.Add(written)
+	wsCompressionSaved.Add(int64(size) - written)
+}
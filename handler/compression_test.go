@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/model"
+
+	"github.com/gorilla/websocket"
+)
+
+// compressingDialer offers permessage-deflate, as browsers do
+var compressingDialer = &websocket.Dialer{EnableCompression: true, HandshakeTimeout: 5 * time.Second}
+
+// joinCompressed connects a client that offers compression to the server of
+// h and joins roomID. It returns the client ID and whether compression was
+// negotiated. The connection is closed, and its server goroutines drained,
+// before the configuration of the test is restored.
+func joinCompressed(t *testing.T, h *Handler, srv *httptest.Server, roomID string) (*websocket.Conn, string, bool) {
+	t.Helper()
+	ws, resp, err := compressingDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	negotiated := strings.Contains(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+
+	var notify struct {
+		ClientID string `json:"client_id"`
+	}
+	json.Unmarshal(readUntil(t, ws, model.MessageTypeNotifyClientID).Payload, &notify)
+	t.Cleanup(func() {
+		ws.Close()
+		waitDisconnected(t, h, notify.ClientID)
+	})
+	sendTestMessage(t, ws, "join", JoinRoomPayload{RoomID: roomID})
+	readUntil(t, ws, model.MessageTypeJoined)
+	return ws, notify.ClientID, negotiated
+}
+
+// TestOfferCompression relays an offer between two clients that offer
+// compression, with WS_COMPRESSION on and off
+func TestOfferCompression(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(map[bool]string{true: "on", false: "off"}[enabled], func(t *testing.T) {
+			setSignaling(t, func(s *config.SignalingConfig) {
+				s.Compression = enabled
+				s.CompressionThreshold = 512
+			})
+			h := newTestHandler()
+			srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+			defer srv.Close()
+
+			sender, _, negotiated := joinCompressed(t, h, srv, "room")
+			receiver, receiverID, _ := joinCompressed(t, h, srv, "room")
+			if negotiated != enabled {
+				t.Fatalf("permessage-deflate negotiated: %v, want %v", negotiated, enabled)
+			}
+			readUntil(t, sender, model.MessageTypeNewClient)
+
+			before := wsCompressedMessages.Value()
+			sendTestMessage(t, sender, "offer", SDPOfferPayload{SDP: testSDP, ClientID: receiverID})
+			var offer SDPOfferPayload
+			json.Unmarshal(readUntil(t, receiver, model.MessageTypeSDPOffer).Payload, &offer)
+			if offer.SDP != testSDP {
+				t.Errorf("received SDP differs from the one sent")
+			}
+
+			// The offer is over the threshold, so it goes out compressed when negotiated
+			compressed := wsCompressedMessages.Value() > before
+			if compressed != enabled {
+				t.Errorf("offer compressed: %v, want %v", compressed, enabled)
+			}
+		})
+	}
+}
+
+// TestReadLimitAfterDecompression sends a message that is small on the wire
+// but exceeds MAX_MESSAGE_SIZE once inflated
+func TestReadLimitAfterDecompression(t *testing.T) {
+	setSignaling(t, func(s *config.SignalingConfig) {
+		s.Compression = true
+		s.MaxMessageSize = 1024
+	})
+	h := newTestHandler()
+	srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+	defer srv.Close()
+
+	ws, _, negotiated := joinCompressed(t, h, srv, "room")
+	if !negotiated {
+		t.Fatal("permessage-deflate was not negotiated")
+	}
+	bomb := `{"type":"broadcast","payload":{"data":"` + strings.Repeat("a", 64*1024) + `"}}`
+	ws.EnableWriteCompression(true)
+	if err := ws.WriteMessage(websocket.TextMessage, []byte(bomb)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var err error
+	for err == nil {
+		_, _, err = ws.ReadMessage()
+	}
+	if !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Fatalf("got %v, want close 1009", err)
+	}
+}
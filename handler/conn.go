@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/model"
+
+	"github.com/gorilla/websocket"
+)
+
+// Conn wraps a client's WebSocket connection. gorilla/websocket allows only
+// one concurrent writer, so every outbound frame (messages, pings, pongs and
+// the close frame) is handed to the goroutine running HandleSendMessage,
+// which is the only code that writes to the socket.
+type Conn struct {
+	ws     *websocket.Conn
+	client *model.Client
+	codec  wireCodec              // encoding negotiated with the WebSocket subprotocol
+	wire   *countingConn          // set when permessage-deflate was negotiated
+	cfg    config.SignalingConfig // the settings of the handler that built it
+
+	pongs     chan []byte
+	closeCh   chan []byte
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newConn(ws *websocket.Conn, client *model.Client, cfg config.SignalingConfig) *Conn {
+	return &Conn{
+		ws:      ws,
+		client:  client,
+		cfg:     cfg,
+		codec:   codecFor(ws.Subprotocol()),
+		pongs:   make(chan []byte, 1),
+		closeCh: make(chan []byte, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// enableCompression compresses the messages that reach the configured threshold
+func (c *Conn) enableCompression(wire *countingConn) {
+	c.wire = wire
+	c.ws.SetCompressionLevel(c.cfg.CompressionLevel)
+}
+
+// Send queues a message for the writer; it reports false once the connection is closed
+func (c *Conn) Send(msg *model.Message) bool {
+	select {
+	case c.client.Send <- msg:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+// Close asks the writer to send a close frame with the given code and shut down
+func (c *Conn) Close(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCh <- websocket.FormatCloseMessage(code, text)
+	})
+}
+
+// handlePing queues the pong reply instead of writing it from the reader
+func (c *Conn) handlePing(data string) error {
+	c.client.Touch()
+	select {
+	case c.pongs <- []byte(data):
+	default:
+		// A pong is already pending; the peer only needs the latest one
+	}
+	return nil
+}
+
+// writeMessage writes a data frame, compressed if it is large enough and the
+// client negotiated compression
+func (c *Conn) writeMessage(messageType int, data []byte) error {
+	compress := c.wire != nil && len(data) >= c.cfg.CompressionThreshold
+	c.ws.EnableWriteCompression(compress)
+	if !compress {
+		return sendMessage(c.ws, messageType, data, c.cfg.WriteWait)
+	}
+
+	before := c.wire.written.Load()
+	if err := sendMessage(c.ws, messageType, data, c.cfg.WriteWait); err != nil {
+		return err
+	}
+	recordCompression(len(data), c.wire.written.Load()-before)
+	return nil
+}
+
+// writeFrame writes a single frame with the configured write deadline
+func (c *Conn) writeFrame(messageType int, data []byte) error {
+	c.ws.SetWriteDeadline(time.Now().Add(c.cfg.WriteWait))
+	return c.ws.WriteMessage(messageType, data)
+}
@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/manager"
+	"gosignaling/model"
+	"gosignaling/repository/mem"
+	"gosignaling/services"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestMain(m *testing.M) {
+	// Every connection and rejected message is logged
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// writeGuard records the goroutines that write to a hijacked connection and
+// whether two writes ever overlapped
+type writeGuard struct {
+	net.Conn
+	tracking atomic.Bool
+	inFlight atomic.Int32
+	overlaps atomic.Int32
+
+	mutex   sync.Mutex
+	writers map[uint64]int
+}
+
+func (g *writeGuard) Write(p []byte) (int, error) {
+	if g.tracking.Load() {
+		if g.inFlight.Add(1) > 1 {
+			g.overlaps.Add(1)
+		}
+		defer g.inFlight.Add(-1)
+
+		id := goroutineID()
+		g.mutex.Lock()
+		g.writers[id]++
+		g.mutex.Unlock()
+	}
+	return g.Conn.Write(p)
+}
+
+func (g *writeGuard) writerCount() int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return len(g.writers)
+}
+
+// goroutineID parses the ID from the "goroutine 123 [running]:" stack header
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	fields := strings.Fields(string(buf[:n]))
+	id, _ := strconv.ParseUint(fields[1], 10, 64)
+	return id
+}
+
+// guardedResponseWriter hands the upgrader a writeGuard when it hijacks the connection
+type guardedResponseWriter struct {
+	http.ResponseWriter
+	guard *writeGuard
+}
+
+func (w *guardedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := w.ResponseWriter.(http.Hijacker).Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	w.guard = &writeGuard{Conn: conn, writers: make(map[uint64]int)}
+	return w.guard, brw, nil
+}
+
+// setSignaling changes config.Signaling for the rest of a test. Call it
+// before newTestHandler: handlers copy the configuration when they are
+// created, so the server goroutines of a test never read the global while
+// the cleanup restores it.
+func setSignaling(t *testing.T, change func(*config.SignalingConfig)) {
+	saved := config.Signaling
+	t.Cleanup(func() { config.Signaling = saved })
+	change(&config.Signaling)
+}
+
+func newTestHandler() *Handler {
+	rm := manager.NewRoomManager(mem.NewRoomRepository(), mem.NewChatRepository(10), nil)
+	return NewHandler(rm, services.NewRateLimiter(config.RateLimitConfig{}), services.NewIceServerService(config.ICEConfig{}, config.TURNServerConfig{}))
+}
+
+func dialTest(t *testing.T, srv *httptest.Server, dialer *websocket.Dialer) *websocket.Conn {
+	t.Helper()
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	ws, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+// waitDisconnected waits until the server goroutines of a client are gone:
+// the writer removes it from the live clients and the reader from its room
+func waitDisconnected(t *testing.T, h *Handler, clientID string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		h.liveMutex.Lock()
+		live := h.live[clientID]
+		h.liveMutex.Unlock()
+		if _, err := h.manager.GetRoomByClientID(clientID); !live && err != nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("client %s is still connected", clientID)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// readUntil reads JSON messages until one of type msgType arrives
+func readUntil(t *testing.T, ws *websocket.Conn, msgType model.MessageType) *model.Message {
+	t.Helper()
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	defer ws.SetReadDeadline(time.Time{})
+	for {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			t.Fatalf("waiting for %s: %v", msgType, err)
+		}
+		var msg model.Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("invalid message %q: %v", data, err)
+		}
+		if msg.Type == msgType {
+			return &msg
+		}
+	}
+}
+
+func sendTestMessage(t *testing.T, ws *websocket.Conn, msgType string, payload interface{}) {
+	t.Helper()
+	data, _ := json.Marshal(payload)
+	msg, _ := json.Marshal(ReceiveMessage{Type: msgType, Payload: data})
+	if err := ws.WriteMessage(websocket.TextMessage, msg); err != nil {
+		t.Fatalf("write %s: %v", msgType, err)
+	}
+}
+
+// TestConnSingleWriter pushes room messages, server pings, pong replies and
+// error responses at one connection at once, and checks that a single
+// goroutine wrote every frame
+func TestConnSingleWriter(t *testing.T) {
+	setSignaling(t, func(s *config.SignalingConfig) {
+		s.PingInterval = 2 * time.Millisecond
+		s.PongWait = 5 * time.Second
+	})
+	h := newTestHandler()
+
+	type served struct {
+		guard *writeGuard
+		conn  *Conn
+		done  chan struct{}
+	}
+	conns := make(chan served, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gw := &guardedResponseWriter{ResponseWriter: w}
+		ws, err := h.upgrader.Upgrade(gw, r, nil)
+		if err != nil {
+			return
+		}
+		// The handshake is written by this goroutine; only frames count
+		gw.guard.tracking.Store(true)
+		s := served{guard: gw.guard, conn: newConn(ws, model.NewClient("user"), h.signaling), done: make(chan struct{})}
+		conns <- s
+		go h.HandleSendMessage(context.Background(), s.conn)
+		h.HandleReceiveMessage(s.conn, h.rateLimiter.NewClientLimiter("test"))
+		close(s.done)
+	}))
+	defer srv.Close()
+
+	ws := dialTest(t, srv, nil)
+	s := <-conns
+	guard := s.guard
+	sendTestMessage(t, ws, "join", JoinRoomPayload{RoomID: "race"})
+	readUntil(t, ws, model.MessageTypeJoined)
+
+	var pings, pongs, errorsSeen, delivered atomic.Int32
+	ws.SetPingHandler(func(data string) error {
+		pings.Add(1)
+		return ws.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	ws.SetPongHandler(func(string) error {
+		pongs.Add(1)
+		return nil
+	})
+
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			var msg model.Message
+			json.Unmarshal(data, &msg)
+			switch msg.Type {
+			case model.MessageTypeError:
+				errorsSeen.Add(1)
+			case model.MessageTypeBroadcast:
+				delivered.Add(1)
+			}
+		}
+	}()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	// Other members of the room sending to the client
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msg := &model.Message{Type: model.MessageTypeBroadcast, Payload: json.RawMessage(`{"data":"x"}`)}
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					h.manager.DeliverToRoom("race", "", msg)
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+	// The client's own traffic: invalid messages answered with errors, and
+	// pings answered with pongs
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			var err error
+			if i%2 == 0 {
+				err = ws.WriteMessage(websocket.TextMessage, []byte("not json"))
+			} else {
+				err = ws.WriteControl(websocket.PingMessage, []byte("p"), time.Now().Add(time.Second))
+			}
+			if err != nil {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	time.Sleep(300 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+	ws.Close()
+	<-readerDone
+	// Both server goroutines must be gone before the configuration is restored
+	<-s.done
+	<-s.conn.done
+
+	if pings.Load() == 0 || pongs.Load() == 0 || errorsSeen.Load() == 0 || delivered.Load() == 0 {
+		t.Fatalf("not all frame kinds were written: %d pings, %d pongs, %d errors, %d messages",
+			pings.Load(), pongs.Load(), errorsSeen.Load(), delivered.Load())
+	}
+	if n := guard.writerCount(); n != 1 {
+		t.Errorf("%d goroutines wrote to the connection, want 1", n)
+	}
+	if n := guard.overlaps.Load(); n != 0 {
+		t.Errorf("%d writes overlapped", n)
+	}
+}
+
+// TestConnCloseWhileSending closes a connection from several goroutines while
+// others keep queueing messages; the client must get exactly one close frame
+func TestConnCloseWhileSending(t *testing.T) {
+	h := newTestHandler()
+
+	conns := make(chan *Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := h.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		c := newConn(ws, model.NewClient("user"), h.signaling)
+		go h.HandleSendMessage(context.Background(), c)
+		conns <- c
+	}))
+	defer srv.Close()
+
+	ws := dialTest(t, srv, nil)
+	c := <-conns
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for c.Send(&model.Message{Type: model.MessageTypeBroadcast, Payload: json.RawMessage(`{}`)}) {
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			time.Sleep(20 * time.Millisecond)
+			c.Close(websocket.CloseGoingAway, "bye")
+		}()
+	}
+
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var err error
+	for err == nil {
+		_, _, err = ws.ReadMessage()
+	}
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Fatalf("got %v, want close 1001", err)
+	}
+	// Send must stop blocking once the writer is gone
+	wg.Wait()
+}
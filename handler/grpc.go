@@ -0,0 +1,487 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sort"
+	"strings"
+
+	"gosignaling/config"
+	"gosignaling/model"
+	"gosignaling/repository"
+	"gosignaling/services"
+	"gosignaling/signalingpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var errEmptyMessage = errors.New("message has no content")
+
+// grpcServer implements the gRPC signaling API. Its clients are ordinary room
+// members, so they can exchange SDP with WebSocket, SSE and HTTP clients.
+type grpcServer struct {
+	signalingpb.UnimplementedSignalingServer
+	h *Handler
+}
+
+// RegisterGRPC registers the gRPC signaling API on s. The server must be
+// created with the GRPCAdminInterceptor.
+func (h *Handler) RegisterGRPC(s *grpc.Server) {
+	signalingpb.RegisterSignalingServer(s, &grpcServer{h: h})
+}
+
+// grpcAdminMethods are the room management RPCs, which need the admin token
+var grpcAdminMethods = map[string]bool{
+	signalingpb.Signaling_ListRooms_FullMethodName:    true,
+	signalingpb.Signaling_GetRoom_FullMethodName:      true,
+	signalingpb.Signaling_CloseRoom_FullMethodName:    true,
+	signalingpb.Signaling_RemoveClient_FullMethodName: true,
+}
+
+// GRPCAdminInterceptor checks the admin bearer token in the "authorization"
+// metadata of the room management RPCs, like the HTTP admin API
+func GRPCAdminInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if grpcAdminMethods[info.FullMethod] {
+		if config.Admin.Token == "" {
+			return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+		}
+		if subtle.ConstantTimeCompare([]byte(grpcBearerToken(ctx)), []byte(config.Admin.Token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "unauthorized")
+		}
+	}
+	return handler(ctx, req)
+}
+
+// grpcBearerToken returns the token of "authorization: Bearer" metadata
+func grpcBearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if scheme, token, ok := strings.Cut(value, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// Signal runs the signaling session of one client. Like HandleSendMessage,
+// it is the only goroutine that sends on the stream; incoming messages are
+// read by receiveSignal.
+func (s *grpcServer) Signal(stream signalingpb.Signaling_SignalServer) error {
+	h := s.h
+	ctx := stream.Context()
+
+	ip := grpcPeerIP(ctx)
+	if err := h.rateLimiter.AcquireConnection(ip); err != nil {
+		log.Printf("Rejected gRPC connection from %s: %v", ip, err)
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	defer h.rateLimiter.ReleaseConnection(ip)
+
+	client := model.NewClient("user")
+	limiter := h.rateLimiter.NewClientLimiter(ip)
+	h.addLiveClient(client.ID)
+	defer func() {
+		h.removeLiveClient(client.ID)
+		log.Printf("gRPC client disconnected: %s", client.ID)
+	}()
+
+	// Send client ID and ICE servers to the newly connected client
+	notify := &signalingpb.NotifyClientID{
+		ClientId:   client.ID,
+		IceServers: toPBIceServers(h.iceServers.IceServers(client.ID, grpcAuthority(ctx))),
+	}
+	if err := stream.Send(&signalingpb.ServerMessage{
+		Message: &signalingpb.ServerMessage_NotifyClientId{NotifyClientId: notify},
+	}); err != nil {
+		return err
+	}
+	log.Printf("New gRPC client connected: %s", client.ID)
+
+	done := make(chan struct{})
+	defer close(done)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.receiveSignal(stream, client, limiter, done)
+		// Only once no message can be processed any more, as on the WebSocket
+		h.manager.LeaveRoom(client)
+	}()
+
+	for {
+		select {
+		case msg := <-client.Send:
+			out, err := toServerMessage(msg)
+			if err != nil {
+				log.Printf("Failed to convert %s message for gRPC client %s: %v", msg.Type, client.ID, err)
+				continue
+			}
+			if err := stream.Send(out); err != nil {
+				log.Printf("Failed to send message: %v", err)
+				return err
+			}
+			if msg.Disconnects() {
+				return nil
+			}
+		case <-client.Disconnected():
+			msg := client.Farewell()
+			if out, err := toServerMessage(msg); err == nil {
+				stream.Send(out)
+			}
+			return nil
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// receiveSignal reads client messages until the client closes its side of
+// the stream, and queues the responses for Signal
+func (s *grpcServer) receiveSignal(stream signalingpb.Signaling_SignalServer, c *model.Client, limiter *services.ClientLimiter, done <-chan struct{}) error {
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		c.Touch()
+
+		var resp *model.Message
+		msgBytes, err := fromClientMessage(in)
+		if err != nil {
+			resp = newErrorMessage("invalid message", err)
+		} else {
+			var ok bool
+			if resp, ok = s.h.processMessage(c, limiter, msgBytes); !ok {
+				log.Printf("Disconnecting gRPC client %s for exceeding rate limits", c.ID)
+				return status.Error(codes.ResourceExhausted, "rate-limited")
+			}
+		}
+		if resp != nil {
+			select {
+			case c.Send <- resp:
+			case <-done:
+				return nil
+			}
+		}
+	}
+}
+
+// ListRooms returns the rooms that have members on this server
+func (s *grpcServer) ListRooms(ctx context.Context, req *signalingpb.ListRoomsRequest) (*signalingpb.ListRoomsResponse, error) {
+	rooms, err := s.h.manager.GetRooms()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &signalingpb.ListRoomsResponse{}
+	for _, room := range rooms {
+		resp.Rooms = append(resp.Rooms, toPBRoom(room))
+	}
+	sort.Slice(resp.Rooms, func(i, j int) bool {
+		return resp.Rooms[i].RoomId < resp.Rooms[j].RoomId
+	})
+	return resp, nil
+}
+
+// GetRoom returns the members of a room on this server
+func (s *grpcServer) GetRoom(ctx context.Context, req *signalingpb.GetRoomRequest) (*signalingpb.Room, error) {
+	if err := validateJoinRoom(&JoinRoomPayload{RoomID: req.RoomId}); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	room, err := s.h.manager.GetRoom(req.RoomId)
+	if err != nil {
+		return nil, grpcRoomError(err)
+	}
+	return toPBRoom(room), nil
+}
+
+// CloseRoom closes a room on every server
+func (s *grpcServer) CloseRoom(ctx context.Context, req *signalingpb.CloseRoomRequest) (*signalingpb.CloseRoomResponse, error) {
+	if err := validateJoinRoom(&JoinRoomPayload{RoomID: req.RoomId}); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(req.Reason) > maxReasonLength {
+		return nil, status.Errorf(codes.InvalidArgument, "reason exceeds %d characters", maxReasonLength)
+	}
+
+	if err := s.h.manager.EndRoom(req.RoomId, req.Reason); err != nil {
+		return nil, grpcRoomError(err)
+	}
+	return &signalingpb.CloseRoomResponse{}, nil
+}
+
+// RemoveClient kicks a member of a room
+func (s *grpcServer) RemoveClient(ctx context.Context, req *signalingpb.RemoveClientRequest) (*signalingpb.RemoveClientResponse, error) {
+	if err := validateJoinRoom(&JoinRoomPayload{RoomID: req.RoomId}); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateClientID(req.ClientId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(req.Reason) > maxReasonLength {
+		return nil, status.Errorf(codes.InvalidArgument, "reason exceeds %d characters", maxReasonLength)
+	}
+
+	if err := s.h.manager.RemoveMember(req.RoomId, req.ClientId, req.Reason); err != nil {
+		return nil, grpcRoomError(err)
+	}
+	return &signalingpb.RemoveClientResponse{}, nil
+}
+
+// grpcRoomError maps a room manager error to a gRPC status
+func grpcRoomError(err error) error {
+	if err == repository.ErrNotFound {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// GetIceServers returns freshly minted ICE servers, like GET /ice-servers
+func (s *grpcServer) GetIceServers(ctx context.Context, req *signalingpb.GetIceServersRequest) (*signalingpb.GetIceServersResponse, error) {
+	ip := grpcPeerIP(ctx)
+	if !s.h.rateLimiter.AllowRequest(ip) {
+		log.Printf("🚦 Rate limited ICE server request from %s", ip)
+		return nil, status.Error(codes.ResourceExhausted, "too many requests")
+	}
+	if !s.h.isLiveClient(req.ClientId) {
+		return nil, status.Error(codes.PermissionDenied, errNoLiveClient)
+	}
+	return &signalingpb.GetIceServersResponse{
+		IceServers: toPBIceServers(s.h.iceServers.IceServers(req.ClientId, grpcAuthority(ctx))),
+		TtlSeconds: int64(s.h.iceServers.TTL().Seconds()),
+	}, nil
+}
+
+// fromClientMessage converts a gRPC client message to the JSON accepted by processMessage
+func fromClientMessage(in *signalingpb.ClientMessage) ([]byte, error) {
+	var (
+		msgType string
+		payload interface{}
+	)
+	switch m := in.Message.(type) {
+	case *signalingpb.ClientMessage_Join:
+		join := JoinRoomPayload{
+			RoomID:    m.Join.RoomId,
+			Name:      m.Join.Name,
+			Metadata:  m.Join.Metadata,
+			HostToken: m.Join.HostToken,
+			Password:  m.Join.Password,
+		}
+		if m.Join.State != nil {
+			join.State = fromPBMediaState(m.Join.State)
+		}
+		msgType, payload = "join", join
+	case *signalingpb.ClientMessage_Offer:
+		msgType, payload = "offer", SDPOfferPayload{SDP: m.Offer.Sdp, ClientID: m.Offer.ClientId}
+	case *signalingpb.ClientMessage_Answer:
+		msgType, payload = "answer", SDPAnswerPayload{SDP: m.Answer.Sdp, ClientID: m.Answer.ClientId}
+	case *signalingpb.ClientMessage_IceCandidate:
+		candidate := IceCandidatePayload{
+			Candidate: m.IceCandidate.Candidate,
+			SdpMid:    m.IceCandidate.SdpMid,
+			ClientID:  m.IceCandidate.ClientId,
+		}
+		if m.IceCandidate.SdpMLineIndex != nil {
+			if *m.IceCandidate.SdpMLineIndex > 0xffff {
+				return nil, fmt.Errorf("sdp_m_line_index %d out of range", *m.IceCandidate.SdpMLineIndex)
+			}
+			index := uint16(*m.IceCandidate.SdpMLineIndex)
+			candidate.SdpMLineIndex = &index
+		}
+		msgType, payload = "ice-candidate", candidate
+	case *signalingpb.ClientMessage_Leave:
+		msgType = "leave"
+	case *signalingpb.ClientMessage_UpdateProfile:
+		profile := UpdateProfilePayload{Name: m.UpdateProfile.Name, Metadata: m.UpdateProfile.Metadata}
+		if m.UpdateProfile.ClearMetadata && len(profile.Metadata) == 0 {
+			profile.Metadata = map[string]string{}
+		}
+		msgType, payload = "update-profile", profile
+	case *signalingpb.ClientMessage_StateUpdate:
+		msgType, payload = "state-update", StateUpdatePayload{
+			AudioMuted:    m.StateUpdate.AudioMuted,
+			VideoMuted:    m.StateUpdate.VideoMuted,
+			ScreenSharing: m.StateUpdate.ScreenSharing,
+			Speaking:      m.StateUpdate.Speaking,
+			HandRaised:    m.StateUpdate.HandRaised,
+		}
+	case *signalingpb.ClientMessage_Raw:
+		return json.Marshal(ReceiveMessage{Type: m.Raw.Type, Payload: m.Raw.Payload})
+	default:
+		return nil, errEmptyMessage
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(ReceiveMessage{Type: msgType, Payload: payloadBytes})
+}
+
+// toServerMessage converts a message queued for a client to its gRPC form;
+// types without a dedicated field are sent as raw JSON
+func toServerMessage(msg *model.Message) (*signalingpb.ServerMessage, error) {
+	switch msg.Type {
+	case model.MessageTypeNotifyClientID:
+		var p struct {
+			ClientID   string            `json:"client_id"`
+			IceServers []model.IceServer `json:"ice_servers"`
+		}
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, err
+		}
+		return &signalingpb.ServerMessage{Message: &signalingpb.ServerMessage_NotifyClientId{
+			NotifyClientId: &signalingpb.NotifyClientID{ClientId: p.ClientID, IceServers: toPBIceServers(p.IceServers)},
+		}}, nil
+	case model.MessageTypeNewClient, model.MessageTypeLeaveClient, model.MessageTypeUpdateProfile, model.MessageTypeStateUpdate:
+		var p model.Participant
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, err
+		}
+		peer := toPBPeer(p)
+		switch msg.Type {
+		case model.MessageTypeNewClient:
+			return &signalingpb.ServerMessage{Message: &signalingpb.ServerMessage_NewClient{NewClient: peer}}, nil
+		case model.MessageTypeUpdateProfile:
+			return &signalingpb.ServerMessage{Message: &signalingpb.ServerMessage_UpdateProfile{UpdateProfile: peer}}, nil
+		case model.MessageTypeStateUpdate:
+			return &signalingpb.ServerMessage{Message: &signalingpb.ServerMessage_StateUpdate{StateUpdate: peer}}, nil
+		}
+		return &signalingpb.ServerMessage{Message: &signalingpb.ServerMessage_LeaveClient{LeaveClient: peer}}, nil
+	case model.MessageTypeSDPOffer, model.MessageTypeSDPAnswer:
+		var p SDPOfferPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, err
+		}
+		sdp := &signalingpb.SessionDescription{ClientId: p.ClientID, Sdp: p.SDP}
+		if msg.Type == model.MessageTypeSDPOffer {
+			return &signalingpb.ServerMessage{Message: &signalingpb.ServerMessage_Offer{Offer: sdp}}, nil
+		}
+		return &signalingpb.ServerMessage{Message: &signalingpb.ServerMessage_Answer{Answer: sdp}}, nil
+	case model.MessageTypeIceCandidate:
+		var p IceCandidatePayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, err
+		}
+		candidate := &signalingpb.IceCandidate{
+			ClientId:  p.ClientID,
+			Candidate: p.Candidate,
+			SdpMid:    p.SdpMid,
+		}
+		if p.SdpMLineIndex != nil {
+			index := uint32(*p.SdpMLineIndex)
+			candidate.SdpMLineIndex = &index
+		}
+		return &signalingpb.ServerMessage{Message: &signalingpb.ServerMessage_IceCandidate{IceCandidate: candidate}}, nil
+	case model.MessageTypeError:
+		var p struct {
+			Error  string `json:"error"`
+			Detail string `json:"detail"`
+		}
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, err
+		}
+		return &signalingpb.ServerMessage{Message: &signalingpb.ServerMessage_Error{
+			Error: &signalingpb.Error{Error: p.Error, Detail: p.Detail},
+		}}, nil
+	default:
+		return &signalingpb.ServerMessage{Message: &signalingpb.ServerMessage_Raw{
+			Raw: &signalingpb.RawMessage{Type: string(msg.Type), Payload: msg.Payload},
+		}}, nil
+	}
+}
+
+func toPBIceServers(servers []model.IceServer) []*signalingpb.IceServer {
+	out := make([]*signalingpb.IceServer, 0, len(servers))
+	for _, s := range servers {
+		out = append(out, &signalingpb.IceServer{
+			Urls:       s.URLs,
+			Username:   s.Username,
+			Credential: s.Credential,
+		})
+	}
+	return out
+}
+
+func toPBRoom(room *model.Room) *signalingpb.Room {
+	out := &signalingpb.Room{
+		RoomId: room.ID,
+		HostId: room.HostID,
+		Locked: room.Locked,
+	}
+	clients := make([]*model.Client, 0, len(room.Clients))
+	for _, c := range room.Clients {
+		clients = append(clients, c)
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].ID < clients[j].ID
+	})
+	for _, c := range clients {
+		out.ClientIds = append(out.ClientIds, c.ID)
+		if !c.Virtual {
+			out.Participants = append(out.Participants, toPBPeer(c.Participant()))
+		}
+	}
+	return out
+}
+
+func toPBPeer(p model.Participant) *signalingpb.Peer {
+	return &signalingpb.Peer{
+		ClientId: p.ClientID,
+		Name:     p.Name,
+		Metadata: p.Metadata,
+		State: &signalingpb.MediaState{
+			AudioMuted:    p.State.AudioMuted,
+			VideoMuted:    p.State.VideoMuted,
+			ScreenSharing: p.State.ScreenSharing,
+			Speaking:      p.State.Speaking,
+			HandRaised:    p.State.HandRaised,
+		},
+	}
+}
+
+func fromPBMediaState(s *signalingpb.MediaState) *model.MediaState {
+	return &model.MediaState{
+		AudioMuted:    s.AudioMuted,
+		VideoMuted:    s.VideoMuted,
+		ScreenSharing: s.ScreenSharing,
+		Speaking:      s.Speaking,
+		HandRaised:    s.HandRaised,
+	}
+}
+
+// grpcPeerIP returns the source IP of a gRPC call
+func grpcPeerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// grpcAuthority returns the host the client connected to, like Request.Host
+func grpcAuthority(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if authority := md.Get(":authority"); len(authority) > 0 {
+		return authority[0]
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/model"
+	"gosignaling/signalingpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newGRPCTestClient serves the gRPC API of h in memory
+func newGRPCTestClient(t *testing.T, h *Handler) signalingpb.SignalingClient {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.UnaryInterceptor(GRPCAdminInterceptor))
+	h.RegisterGRPC(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return signalingpb.NewSignalingClient(cc)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestGRPCAdminAuth(t *testing.T) {
+	saved := config.Admin
+	t.Cleanup(func() { config.Admin = saved })
+	client := newGRPCTestClient(t, newTestHandler())
+
+	config.Admin.Token = ""
+	if _, err := client.ListRooms(withToken("secret"), &signalingpb.ListRoomsRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("admin API disabled: got %v, want PermissionDenied", err)
+	}
+
+	config.Admin.Token = "secret"
+	if _, err := client.ListRooms(context.Background(), &signalingpb.ListRoomsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("no token: got %v, want Unauthenticated", err)
+	}
+	if _, err := client.GetRoom(withToken("wrong"), &signalingpb.GetRoomRequest{RoomId: "room"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("wrong token: got %v, want Unauthenticated", err)
+	}
+	if _, err := client.CloseRoom(context.Background(), &signalingpb.CloseRoomRequest{RoomId: "room"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("CloseRoom without token: got %v, want Unauthenticated", err)
+	}
+	if _, err := client.ListRooms(withToken("secret"), &signalingpb.ListRoomsRequest{}); err != nil {
+		t.Errorf("admin token: %v", err)
+	}
+	if _, err := client.GetRoom(withToken("secret"), &signalingpb.GetRoomRequest{RoomId: "room"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown room: got %v, want NotFound", err)
+	}
+}
+
+func TestGRPCRoomManagement(t *testing.T) {
+	saved := config.Admin
+	t.Cleanup(func() { config.Admin = saved })
+	config.Admin.Token = "secret"
+	h := newTestHandler()
+	client := newGRPCTestClient(t, h)
+	srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+	defer srv.Close()
+
+	join := func() (string, func(model.MessageType) *model.Message) {
+		ws := dialTest(t, srv, nil)
+		var notify struct {
+			ClientID string `json:"client_id"`
+		}
+		json.Unmarshal(readUntil(t, ws, model.MessageTypeNotifyClientID).Payload, &notify)
+		sendTestMessage(t, ws, "join", JoinRoomPayload{RoomID: "room"})
+		readUntil(t, ws, model.MessageTypeJoined)
+		return notify.ClientID, func(msgType model.MessageType) *model.Message { return readUntil(t, ws, msgType) }
+	}
+	first, readFirst := join()
+	_, readSecond := join()
+
+	if _, err := client.RemoveClient(withToken("secret"), &signalingpb.RemoveClientRequest{RoomId: "room", ClientId: first, Reason: "spam"}); err != nil {
+		t.Fatalf("RemoveClient: %v", err)
+	}
+	readFirst(model.MessageTypeKicked)
+	readSecond(model.MessageTypeLeaveClient)
+
+	if _, err := client.CloseRoom(withToken("secret"), &signalingpb.CloseRoomRequest{RoomId: "room"}); err != nil {
+		t.Fatalf("CloseRoom: %v", err)
+	}
+	readSecond(model.MessageTypeRoomClosed)
+	if _, err := client.CloseRoom(withToken("secret"), &signalingpb.CloseRoomRequest{RoomId: "room"}); status.Code(err) != codes.NotFound {
+		t.Errorf("closed room: got %v, want NotFound", err)
+	}
+}
+
+// TestGRPCStateUpdateCarriesProfile checks that gRPC clients get the name of
+// a member with its state, as WebSocket clients do
+func TestGRPCStateUpdateCarriesProfile(t *testing.T) {
+	h := newTestHandler()
+	client := newGRPCTestClient(t, h)
+	srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Signal(ctx)
+	if err != nil {
+		t.Fatalf("Signal: %v", err)
+	}
+	// recvUntil reads server messages until match returns true
+	recvUntil := func(what string, match func(*signalingpb.ServerMessage) bool) *signalingpb.ServerMessage {
+		t.Helper()
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("waiting for %s: %v", what, err)
+			}
+			if match(msg) {
+				return msg
+			}
+		}
+	}
+	stream.Send(&signalingpb.ClientMessage{Message: &signalingpb.ClientMessage_Join{Join: &signalingpb.Join{RoomId: "room"}}})
+	recvUntil("joined", func(m *signalingpb.ServerMessage) bool {
+		return m.GetRaw() != nil && m.GetRaw().Type == string(model.MessageTypeJoined)
+	})
+
+	alice, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room", Name: "Alice"})
+	recvUntil("new_client", func(m *signalingpb.ServerMessage) bool { return m.GetNewClient() != nil })
+	sendTestMessage(t, alice, "state-update", model.MediaState{AudioMuted: true})
+
+	peer := recvUntil("state_update", func(m *signalingpb.ServerMessage) bool { return m.GetStateUpdate() != nil }).GetStateUpdate()
+	if peer.Name != "Alice" || !peer.State.AudioMuted {
+		t.Errorf("state_update: got name %q and %+v, want Alice with audio muted", peer.Name, peer.State)
+	}
+}
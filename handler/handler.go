@@ -4,11 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
+	"gosignaling/config"
 	"gosignaling/manager"
 	"gosignaling/model"
+	"gosignaling/repository"
+	"gosignaling/services"
 
 	"github.com/gorilla/websocket"
 )
@@ -16,6 +22,8 @@ import (
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	// Wire encodings in order of preference; see codec.go
+	Subprotocols: []string{subprotocolProtobuf, subprotocolMsgpack, subprotocolJSON},
 	CheckOrigin: func(r *http.Request) bool {
 		return true // Allow all origins
 	},
@@ -23,94 +31,218 @@ var upgrader = websocket.Upgrader{
 
 // Handler handles WebSocket connections
 type Handler struct {
-	manager *manager.RoomManager
+	manager     *manager.RoomManager
+	rateLimiter *services.RateLimiter
+	iceServers  *services.IceServerService
+	upgrader    websocket.Upgrader
+	// signaling is config.Signaling as of NewHandler; connections read this
+	// copy so that nothing reads the global while they are served
+	signaling config.SignalingConfig
+
+	sessionsMutex sync.Mutex
+	sessions      map[string]*httpSession // WHIP/WHEP sessions by session ID
+	sseSessions   map[string]*sseSession  // SSE sessions by session ID
+	sseRelay      SSERelay                // nil without Redis
+
+	liveMutex sync.Mutex
+	live      map[string]bool // client IDs with an open WebSocket, SSE or gRPC connection
 }
 
 // NewHandler creates a new handler
-func NewHandler(mgr *manager.RoomManager) *Handler {
+func NewHandler(mgr *manager.RoomManager, rateLimiter *services.RateLimiter, iceServers *services.IceServerService) *Handler {
+	u := upgrader
+	u.EnableCompression = config.Signaling.Compression
+
 	return &Handler{
-		manager: mgr,
+		manager:     mgr,
+		rateLimiter: rateLimiter,
+		iceServers:  iceServers,
+		upgrader:    u,
+		signaling:   config.Signaling,
+		sessions:    make(map[string]*httpSession),
+		sseSessions: make(map[string]*sseSession),
+		live:        make(map[string]bool),
 	}
 }
 
 // CreateConnection handles WebSocket connection establishment
 func (h *Handler) CreateConnection(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	ip := clientIP(r)
+	if err := h.rateLimiter.AcquireConnection(ip); err != nil {
+		log.Printf("Rejected connection from %s: %v", ip, err)
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	cw := &countingResponseWriter{ResponseWriter: w}
+	conn, err := h.upgrader.Upgrade(cw, r, nil)
 	if err != nil {
 		log.Printf("Failed to upgrade connection: %v", err)
+		h.rateLimiter.ReleaseConnection(ip)
 		return
 	}
 
 	client := model.NewClient("user")
 	ctx := context.Background()
+	limiter := h.rateLimiter.NewClientLimiter(ip)
+	c := newConn(conn, client, h.signaling)
+	h.addLiveClient(client.ID)
+	if h.upgrader.EnableCompression && offersDeflate(r) {
+		c.enableCompression(cw.conn)
+	}
 
-	// Start goroutines for sending and receiving messages
-	go h.HandleSendMessage(ctx, client, conn)
-	go h.HandleReceiveMessage(client, conn)
+	// Send client ID and ICE servers to the newly connected client
+	c.Send(h.newNotifyClientIDMessage(client, r, nil))
 
-	// Send client ID to the newly connected client
-	payload, _ := json.Marshal(map[string]string{"client_id": client.ID})
-	msg := &model.Message{
-		Type:    model.MessageTypeNotifyClientID,
-		Payload: payload,
-	}
-	msgBytes, _ := json.Marshal(msg)
-	sendMessage(conn, msgBytes)
+	// Start goroutines for sending and receiving messages
+	go h.HandleSendMessage(ctx, c)
+	go func() {
+		defer h.rateLimiter.ReleaseConnection(ip)
+		h.HandleReceiveMessage(c, limiter)
+	}()
 
 	log.Printf("New client connected: %s", client.ID)
 }
 
-// HandleSendMessage handles sending messages to a client
-func (h *Handler) HandleSendMessage(ctx context.Context, c *model.Client, conn *websocket.Conn) {
-	ticker := time.NewTicker(30 * time.Second)
+// HandleSendMessage handles sending messages to a client. It is the only
+// goroutine that writes to the connection.
+func (h *Handler) HandleSendMessage(ctx context.Context, conn *Conn) {
+	c := conn.client
+	ticker := time.NewTicker(conn.cfg.PingInterval)
 	defer func() {
 		ticker.Stop()
-		conn.Close()
-		h.manager.LeaveRoom(c)
+		close(conn.done)
+		conn.ws.Close()
+		h.removeLiveClient(c.ID)
 		log.Printf("Client disconnected: %s", c.ID)
 	}()
 
 	for {
 		select {
 		case msg := <-c.Send:
-			msgBytes, err := json.Marshal(msg)
+			msgBytes, err := conn.codec.encode(msg)
 			if err != nil {
 				log.Printf("Failed to marshal message: %v", err)
 				return
 			}
-			if err := sendMessage(conn, msgBytes); err != nil {
+			if err := conn.writeMessage(conn.codec.frameType(), msgBytes); err != nil {
 				log.Printf("Failed to send message: %v", err)
 				return
 			}
+			if msg.Disconnects() {
+				conn.writeFrame(websocket.CloseMessage, disconnectFrame(msg))
+				return
+			}
+		case <-c.Disconnected():
+			// Written even when Send is full
+			msg := c.Farewell()
+			if msgBytes, err := conn.codec.encode(msg); err == nil {
+				conn.writeMessage(conn.codec.frameType(), msgBytes)
+			}
+			conn.writeFrame(websocket.CloseMessage, disconnectFrame(msg))
+			return
+		case data := <-conn.pongs:
+			if err := conn.writeFrame(websocket.PongMessage, data); err != nil {
+				log.Printf("Pong failed, client disconnected: %s", c.ID)
+				return
+			}
 		case <-ticker.C:
-			// Send ping to keep connection alive
-			if err := sendMessage(conn, []byte(`{"type":"ping"}`)); err != nil {
+			// Send ping to keep connection alive; the pong extends the read deadline
+			if err := conn.writeFrame(websocket.PingMessage, nil); err != nil {
 				log.Printf("Ping failed, client disconnected: %s", c.ID)
 				return
 			}
+			if _, isJSON := conn.codec.(jsonCodec); isJSON && conn.cfg.LegacyJSONPing {
+				if err := conn.writeMessage(websocket.TextMessage, []byte(`{"type":"ping"}`)); err != nil {
+					log.Printf("Ping failed, client disconnected: %s", c.ID)
+					return
+				}
+			}
+		case closeMsg := <-conn.closeCh:
+			conn.writeFrame(websocket.CloseMessage, closeMsg)
+			return
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-// HandleReceiveMessage handles receiving messages from a client
-func (h *Handler) HandleReceiveMessage(c *model.Client, conn *websocket.Conn) {
-	defer conn.Close()
+// disconnectFrame returns the close frame that follows a message after which
+// the client is disconnected
+func disconnectFrame(msg *model.Message) []byte {
+	code := websocket.CloseNormalClosure
+	if msg.Type == model.MessageTypeKicked {
+		code = websocket.ClosePolicyViolation
+	}
+	return websocket.FormatCloseMessage(code, string(msg.Type))
+}
+
+// HandleReceiveMessage handles receiving messages from a client. The client
+// leaves its room when it returns: a join read before the writer closed the
+// connection is processed first, so it cannot leave a member behind.
+func (h *Handler) HandleReceiveMessage(conn *Conn, limiter *services.ClientLimiter) {
+	c := conn.client
+	ws := conn.ws
+	defer h.manager.LeaveRoom(c)
+	defer conn.Close(websocket.CloseNormalClosure, "")
+	ws.SetReadLimit(conn.cfg.MaxMessageSize)
+	ws.SetReadDeadline(time.Now().Add(conn.cfg.PongWait))
+	// Any frame from the client, including pongs, counts as activity
+	ws.SetPongHandler(func(string) error {
+		c.Touch()
+		return ws.SetReadDeadline(time.Now().Add(conn.cfg.PongWait))
+	})
+	ws.SetPingHandler(conn.handlePing)
 
 	for {
-		_, msgBytes, err := conn.ReadMessage()
+		msgBytes, err := readMessage(ws, conn.cfg.MaxMessageSize)
+		if err == websocket.ErrReadLimit {
+			log.Printf("Client %s sent a message larger than %d bytes, disconnecting", c.ID, conn.cfg.MaxMessageSize)
+			conn.Close(websocket.CloseMessageTooBig, "message too big")
+			return
+		}
 		if err != nil {
 			log.Printf("Error reading message from client %s: %v", c.ID, err)
 			return
 		}
+		ws.SetReadDeadline(time.Now().Add(conn.cfg.PongWait))
+		c.Touch()
 
-		var req ReceiveMessage
-		if err := json.Unmarshal(msgBytes, &req); err != nil {
-			log.Printf("Failed to unmarshal message: %v", err)
+		if msgBytes, err = conn.codec.decode(msgBytes); err != nil {
+			log.Printf("Failed to decode message from client %s: %v", c.ID, err)
+			conn.Send(newErrorMessage("invalid message", err))
 			continue
 		}
 
+		resp, ok := h.processMessage(c, limiter, msgBytes)
+		if !ok {
+			log.Printf("Disconnecting client %s for exceeding rate limits", c.ID)
+			conn.Close(websocket.ClosePolicyViolation, "rate-limited")
+			return
+		}
+		if resp != nil {
+			conn.Send(resp)
+		}
+	}
+}
+
+// processMessage handles one incoming signaling message regardless of the
+// transport it arrived on; the transport records the client's activity. It returns the response for the sender, if any,
+// and false when the sender must be disconnected for abuse.
+func (h *Handler) processMessage(c *model.Client, limiter *services.ClientLimiter, msgBytes []byte) (*model.Message, bool) {
+	var req ReceiveMessage
+	if err := json.Unmarshal(msgBytes, &req); err != nil {
+		log.Printf("Failed to unmarshal message: %v", err)
+		return newErrorMessage("invalid message", err), true
+	}
+
+	if !limiter.Allow(req.Type) {
+		if limiter.Exceeded() {
+			return nil, false
+		}
+		return newErrorMessage("rate-limited", nil), true
+	}
+
 	var resp *model.Message
 	switch req.Type {
 	case "join":
@@ -121,15 +253,100 @@ func (h *Handler) HandleReceiveMessage(c *model.Client, conn *websocket.Conn) {
 		resp = h.handleSDPAnswer(c, req.Payload)
 	case "ice-candidate":
 		resp = h.handleIceCandidate(c, req.Payload)
+	case "leave":
+		resp = h.handleLeaveRoom(c)
+	case "broadcast":
+		resp = h.handleBroadcast(c, req.Payload)
+	case "direct":
+		resp = h.handleDirect(c, req.Payload)
+	case "update-profile":
+		resp = h.handleUpdateProfile(c, req.Payload)
+	case "state-update":
+		resp = h.handleStateUpdate(c, req.Payload)
+	case "kick":
+		resp = h.handleKick(c, req.Payload)
+	case "mute-request":
+		resp = h.handleMuteRequest(c, req.Payload)
+	case "lock-room":
+		resp = h.handleLockRoom(c, req.Payload)
+	case "transfer-host":
+		resp = h.handleTransferHost(c, req.Payload)
+	case "admit":
+		resp = h.handleAdmit(c, req.Payload)
+	case "deny":
+		resp = h.handleDeny(c, req.Payload)
+	case "breakout":
+		resp = h.handleBreakout(c, req.Payload)
+	case "recall":
+		resp = h.handleRecall(c)
+	case "chat":
+		resp = h.handleChat(c, req.Payload)
+	case "chat-edit":
+		resp = h.handleChatEdit(c, req.Payload)
+	case "chat-delete":
+		resp = h.handleChatDelete(c, req.Payload)
+	case "ping":
+		// Only keeps an otherwise quiet client from being disconnected as idle
 	default:
 		log.Printf("Unknown message type: %s", req.Type)
+		resp = newErrorMessage("unknown message type", nil)
 	}
+	return resp, true
+}
 
-	if resp != nil {
-		respBytes, _ := json.Marshal(resp)
-		sendMessage(conn, respBytes)
+// GetIceServers returns freshly minted ICE servers, e.g. to renew TURN
+// credentials before they expire. Credentials are only issued for a client
+// with an open signaling connection on this pod.
+func (h *Handler) GetIceServers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ip := clientIP(r)
+	if !h.rateLimiter.AllowRequest(ip) {
+		log.Printf("🚦 Rate limited ICE server request from %s", ip)
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+		return
+	}
+	clientID := r.URL.Query().Get("client_id")
+	if !h.isLiveClient(clientID) {
+		http.Error(w, errNoLiveClient, http.StatusForbidden)
+		return
 	}
+
+	resp := map[string]interface{}{
+		"ice_servers": h.iceServers.IceServers(clientID, r.Host),
+		"ttl":         int(h.iceServers.TTL().Seconds()),
 	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
+
+const errNoLiveClient = "client_id has no open signaling connection"
+
+// addLiveClient records a client with an open signaling connection
+func (h *Handler) addLiveClient(clientID string) {
+	h.liveMutex.Lock()
+	defer h.liveMutex.Unlock()
+	h.live[clientID] = true
+}
+
+// removeLiveClient forgets a client once its connection is closed
+func (h *Handler) removeLiveClient(clientID string) {
+	h.liveMutex.Lock()
+	defer h.liveMutex.Unlock()
+	delete(h.live, clientID)
+}
+
+// isLiveClient reports whether a client has an open signaling connection.
+// This handler only knows its own connections; clients of other pods count
+// once they are in a room, which the room repository shares.
+func (h *Handler) isLiveClient(clientID string) bool {
+	h.liveMutex.Lock()
+	live := h.live[clientID]
+	h.liveMutex.Unlock()
+	return live || h.manager.IsMember(clientID)
 }
 
 // ReceiveMessage represents an incoming message
@@ -141,6 +358,15 @@ type ReceiveMessage struct {
 // JoinRoomPayload represents the payload for joining a room
 type JoinRoomPayload struct {
 	RoomID string `json:"room_id"`
+	// Name and Metadata optionally set the client's profile before joining
+	Name     string            `json:"name,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// State optionally sets the client's media state before joining
+	State *model.MediaState `json:"state,omitempty"`
+	// HostToken claims the host role of the room
+	HostToken string `json:"host_token,omitempty"`
+	// Password opens a persistent room that has one
+	Password string `json:"password,omitempty"`
 }
 
 func (h *Handler) handleJoinRoom(c *model.Client, payload json.RawMessage) *model.Message {
@@ -152,15 +378,51 @@ func (h *Handler) handleJoinRoom(c *model.Client, payload json.RawMessage) *mode
 			Payload: []byte(`{"error":"invalid payload"}`),
 		}
 	}
+	if err := validateJoinRoom(&joinPayload); err != nil {
+		log.Printf("Rejected join room payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	asHost := false
+	if joinPayload.HostToken != "" {
+		if err := services.VerifyHostToken(config.Host.TokenSecret, joinPayload.RoomID, joinPayload.HostToken, time.Now()); err != nil {
+			log.Printf("Rejected host token from %s: %v", c.ID, err)
+			return newErrorMessage("invalid payload", err)
+		}
+		asHost = true
+	}
 
-	if err := h.manager.JoinRoom(c, joinPayload.RoomID); err != nil {
+	// Set the profile first so that new-client carries it
+	if joinPayload.Name != "" || joinPayload.Metadata != nil {
+		c.SetProfile(mergeProfile(c.Profile(), joinPayload.Name, joinPayload.Metadata))
+	}
+	if joinPayload.State != nil {
+		c.SetState(*joinPayload.State)
+	}
+
+	if err := h.manager.JoinRoom(c, joinPayload.RoomID, joinPayload.Password, asHost); err != nil {
+		if err == manager.ErrWaitingForAdmission {
+			return newWaitingMessage(joinPayload.RoomID)
+		}
 		log.Printf("Failed to join room: %v", err)
+		if err == repository.ErrRoomLocked || err == repository.ErrRoomFull || err == repository.ErrRoomExpired || err == manager.ErrWrongPassword ||
+			err == manager.ErrBreakoutRoom {
+			return newErrorMessage("failed to join room", err)
+		}
 		return &model.Message{
 			Type:    model.MessageTypeError,
 			Payload: []byte(`{"error":"failed to join room"}`),
 		}
 	}
 
+	return h.manager.JoinedMessage(c, joinPayload.RoomID)
+}
+
+func (h *Handler) handleLeaveRoom(c *model.Client) *model.Message {
+	if err := h.manager.LeaveRoom(c); err != nil && err != repository.ErrNotFound {
+		log.Printf("Failed to leave room: %v", err)
+		return newErrorMessage("failed to leave room", nil)
+	}
 	return nil
 }
 
@@ -179,6 +441,10 @@ func (h *Handler) handleSDPOffer(c *model.Client, payload json.RawMessage) *mode
 			Payload: []byte(`{"error":"invalid payload"}`),
 		}
 	}
+	if err := validateSDPPayload(offerPayload.SDP, offerPayload.ClientID); err != nil {
+		log.Printf("Rejected SDP offer payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
 
 	sdp := &model.SDP{
 		Type: "offer",
@@ -211,6 +477,10 @@ func (h *Handler) handleSDPAnswer(c *model.Client, payload json.RawMessage) *mod
 			Payload: []byte(`{"error":"invalid payload"}`),
 		}
 	}
+	if err := validateSDPPayload(answerPayload.SDP, answerPayload.ClientID); err != nil {
+		log.Printf("Rejected SDP answer payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
 
 	sdp := &model.SDP{
 		Type: "answer",
@@ -245,6 +515,10 @@ func (h *Handler) handleIceCandidate(c *model.Client, payload json.RawMessage) *
 			Payload: []byte(`{"error":"invalid payload"}`),
 		}
 	}
+	if err := validateIceCandidatePayload(&iceCandidatePayload); err != nil {
+		log.Printf("Rejected ICE candidate payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
 
 	iceCandidate := &model.IceCandidate{
 		Candidate:     iceCandidatePayload.Candidate,
@@ -264,8 +538,116 @@ func (h *Handler) handleIceCandidate(c *model.Client, payload json.RawMessage) *
 	return nil
 }
 
-func sendMessage(conn *websocket.Conn, msg []byte) error {
-	w, err := conn.NextWriter(websocket.TextMessage)
+// BroadcastPayload represents the payload for a broadcast to the room
+type BroadcastPayload struct {
+	Data json.RawMessage `json:"data"`
+}
+
+func (h *Handler) handleBroadcast(c *model.Client, payload json.RawMessage) *model.Message {
+	if !config.AppMessages.Enabled("broadcast") {
+		return newErrorMessage("message type disabled", nil)
+	}
+	var broadcastPayload BroadcastPayload
+	if err := json.Unmarshal(payload, &broadcastPayload); err != nil {
+		log.Printf("Failed to unmarshal broadcast payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateAppData(broadcastPayload.Data); err != nil {
+		log.Printf("Rejected broadcast payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	if err := h.manager.Broadcast(c, broadcastPayload.Data); err != nil {
+		log.Printf("Failed to broadcast: %v", err)
+		return newErrorMessage("failed to broadcast", nil)
+	}
+
+	return nil
+}
+
+// DirectPayload represents the payload for a message to one room member
+type DirectPayload struct {
+	ClientID string          `json:"client_id"`
+	Data     json.RawMessage `json:"data"`
+}
+
+func (h *Handler) handleDirect(c *model.Client, payload json.RawMessage) *model.Message {
+	if !config.AppMessages.Enabled("direct") {
+		return newErrorMessage("message type disabled", nil)
+	}
+	var directPayload DirectPayload
+	if err := json.Unmarshal(payload, &directPayload); err != nil {
+		log.Printf("Failed to unmarshal direct payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateClientID(directPayload.ClientID); err != nil {
+		log.Printf("Rejected direct payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateAppData(directPayload.Data); err != nil {
+		log.Printf("Rejected direct payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	if err := h.manager.SendDirect(c, directPayload.Data, directPayload.ClientID); err != nil {
+		log.Printf("Failed to send direct message: %v", err)
+		return newErrorMessage("failed to send direct message", nil)
+	}
+
+	return nil
+}
+
+// newNotifyClientIDMessage tells a new client its ID and ICE servers;
+// extra carries transport specific fields
+func (h *Handler) newNotifyClientIDMessage(client *model.Client, r *http.Request, extra map[string]interface{}) *model.Message {
+	body := map[string]interface{}{
+		"client_id":   client.ID,
+		"ice_servers": h.iceServers.IceServers(client.ID, r.Host),
+	}
+	for k, v := range extra {
+		body[k] = v
+	}
+	payload, _ := json.Marshal(body)
+	return &model.Message{
+		Type:    model.MessageTypeNotifyClientID,
+		Payload: payload,
+	}
+}
+
+// newErrorMessage builds an error message; detail explains why a request was rejected
+func newErrorMessage(reason string, detail error) *model.Message {
+	body := map[string]string{"error": reason}
+	if detail != nil {
+		body["detail"] = detail.Error()
+	}
+	payload, _ := json.Marshal(body)
+	return &model.Message{
+		Type:    model.MessageTypeError,
+		Payload: payload,
+	}
+}
+
+// clientIP returns the source IP of a request, honoring proxy headers when trusted
+func clientIP(r *http.Request) string {
+	if config.RateLimit.TrustProxyHeaders {
+		if ip := r.Header.Get("Fly-Client-IP"); ip != "" {
+			return ip
+		}
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			ip, _, _ := strings.Cut(xff, ",")
+			return strings.TrimSpace(ip)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func sendMessage(conn *websocket.Conn, messageType int, msg []byte, writeWait time.Duration) error {
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	w, err := conn.NextWriter(messageType)
 	if err != nil {
 		return err
 	}
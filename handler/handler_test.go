@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/manager"
+	"gosignaling/model"
+	"gosignaling/repository/mem"
+	"gosignaling/services"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestGetIceServers(t *testing.T) {
+	rl := services.NewRateLimiter(config.RateLimitConfig{
+		Enabled: true,
+		IP:      config.Rate{PerSecond: 0.001, Burst: 3},
+	})
+	rm := manager.NewRoomManager(mem.NewRoomRepository(), mem.NewChatRepository(10), nil)
+	h := NewHandler(rm, rl, services.NewIceServerService(config.ICEConfig{}, config.TURNServerConfig{}))
+	srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+	defer srv.Close()
+
+	ws := dialTest(t, srv, nil)
+	var notify struct {
+		ClientID string `json:"client_id"`
+	}
+	json.Unmarshal(readUntil(t, ws, model.MessageTypeNotifyClientID).Payload, &notify)
+
+	get := func(clientID string) int {
+		w := httptest.NewRecorder()
+		h.GetIceServers(w, httptest.NewRequest(http.MethodGet, "/ice-servers?client_id="+clientID, nil))
+		return w.Code
+	}
+	if code := get(notify.ClientID); code != http.StatusOK {
+		t.Errorf("connected client: got %d, want 200", code)
+	}
+	if code := get("unknown"); code != http.StatusForbidden {
+		t.Errorf("unknown client: got %d, want 403", code)
+	}
+	// The two requests above took two of the three tokens of the address
+	get(notify.ClientID)
+	if code := get(notify.ClientID); code != http.StatusTooManyRequests {
+		t.Errorf("over the IP limit: got %d, want 429", code)
+	}
+
+	// Credentials stop once the connection is gone
+	ws.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for h.isLiveClient(notify.ClientID) {
+		if time.Now().After(deadline) {
+			t.Fatal("client still live after disconnecting")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// joinAs connects a client to srv and joins roomID with the given payload,
+// returning the joined message
+func joinAs(t *testing.T, srv *httptest.Server, join JoinRoomPayload) (*websocket.Conn, *model.Message) {
+	t.Helper()
+	ws := dialTest(t, srv, nil)
+	readUntil(t, ws, model.MessageTypeNotifyClientID)
+	sendTestMessage(t, ws, "join", join)
+	return ws, readUntil(t, ws, model.MessageTypeJoined)
+}
+
+// TestJoinAnnouncesProfile checks that new-client and the participants of
+// joined carry profiles and media state, including changes made after joining
+func TestJoinAnnouncesProfile(t *testing.T) {
+	h := newTestHandler()
+	srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+	defer srv.Close()
+
+	alice, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room", Name: "Alice"})
+	bob, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room", Name: "Bob"})
+	var p model.Participant
+	json.Unmarshal(readUntil(t, alice, model.MessageTypeNewClient).Payload, &p)
+	if p.Name != "Bob" {
+		t.Errorf("new-client name: got %q, want Bob", p.Name)
+	}
+
+	sendTestMessage(t, alice, "update-profile", model.Profile{Name: "Alice B."})
+	readUntil(t, bob, model.MessageTypeUpdateProfile)
+	sendTestMessage(t, bob, "state-update", model.MediaState{AudioMuted: true, HandRaised: true})
+	readUntil(t, alice, model.MessageTypeStateUpdate)
+
+	_, joined := joinAs(t, srv, JoinRoomPayload{RoomID: "room", Name: "Carol"})
+	var state struct {
+		Participants []model.Participant `json:"participants"`
+	}
+	json.Unmarshal(joined.Payload, &state)
+	byName := map[string]model.Participant{}
+	for _, p := range state.Participants {
+		byName[p.Name] = p
+	}
+	if _, ok := byName["Alice B."]; len(state.Participants) != 2 || !ok {
+		t.Errorf("participants: got %+v, want Alice B. and Bob", state.Participants)
+	}
+	if s := byName["Bob"].State; !s.AudioMuted || !s.HandRaised {
+		t.Errorf("state of Bob: got %+v, want audio muted and hand raised", s)
+	}
+}
+
+// TestHostPassesOn checks that the host role passes to the member that
+// joined first when the host leaves, and that late joiners see the new host
+func TestHostPassesOn(t *testing.T) {
+	h := newTestHandler()
+	srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+	defer srv.Close()
+
+	hostOf := func(joined *model.Message) string {
+		var state struct {
+			HostID string `json:"host_id"`
+		}
+		json.Unmarshal(joined.Payload, &state)
+		return state.HostID
+	}
+	host, joined := joinAs(t, srv, JoinRoomPayload{RoomID: "room"})
+	hostID := hostOf(joined)
+	second, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room"})
+	joinAs(t, srv, JoinRoomPayload{RoomID: "room"})
+
+	sendTestMessage(t, host, "leave", nil)
+	var changed struct {
+		ClientID string `json:"client_id"`
+	}
+	json.Unmarshal(readUntil(t, second, model.MessageTypeHostChanged).Payload, &changed)
+	if changed.ClientID == "" || changed.ClientID == hostID {
+		t.Fatalf("host-changed names %q after host %s left", changed.ClientID, hostID)
+	}
+
+	if _, joined := joinAs(t, srv, JoinRoomPayload{RoomID: "room"}); hostOf(joined) != changed.ClientID {
+		t.Errorf("late joiner sees host %q, want %s", hostOf(joined), changed.ClientID)
+	}
+}
+
+// TestBreakoutRoomID checks that members are moved into a breakout room whose
+// ID is usable in WHIP and WHEP paths, and that nobody joins it directly
+func TestBreakoutRoomID(t *testing.T) {
+	h := newTestHandler()
+	srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+	defer srv.Close()
+
+	host, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room"})
+	member, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room"})
+	var p model.Participant
+	json.Unmarshal(readUntil(t, host, model.MessageTypeNewClient).Payload, &p)
+
+	sendTestMessage(t, host, "breakout", BreakoutPayload{Rooms: map[string][]string{"a": {p.ClientID}}})
+	var moved struct {
+		RoomID       string `json:"room_id"`
+		ParentRoomID string `json:"parent_room_id"`
+	}
+	json.Unmarshal(readUntil(t, member, model.MessageTypeMovedToRoom).Payload, &moved)
+	if moved.RoomID != "room:a" || moved.ParentRoomID != "room" {
+		t.Fatalf("moved to %q with parent %q, want room:a with parent room", moved.RoomID, moved.ParentRoomID)
+	}
+	readUntil(t, host, model.MessageTypeLeaveClient)
+
+	ws := dialTest(t, srv, nil)
+	readUntil(t, ws, model.MessageTypeNotifyClientID)
+	sendTestMessage(t, ws, "join", JoinRoomPayload{RoomID: "room:a"})
+	var resp struct {
+		Error   string `json:"error"`
+		Details string `json:"details"`
+	}
+	json.Unmarshal(readUntil(t, ws, model.MessageTypeError).Payload, &resp)
+	if resp.Error != "failed to join room" {
+		t.Errorf("direct join of a breakout room: got %+v, want failed to join room", resp)
+	}
+}
+
+// TestPongCountsAsActivity checks that a quiet WebSocket client answering
+// pings is not idle, as browsers never send application messages on their own
+func TestPongCountsAsActivity(t *testing.T) {
+	h := newTestHandler()
+	srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+	defer srv.Close()
+
+	ws := dialTest(t, srv, nil)
+	var notify struct {
+		ClientID string `json:"client_id"`
+	}
+	json.Unmarshal(readUntil(t, ws, model.MessageTypeNotifyClientID).Payload, &notify)
+	sendTestMessage(t, ws, "join", JoinRoomPayload{RoomID: "room"})
+	readUntil(t, ws, model.MessageTypeJoined)
+	c, err := h.manager.GetClientByID(notify.ClientID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := c.LastActive()
+
+	time.Sleep(10 * time.Millisecond)
+	if err := ws.WriteControl(websocket.PongMessage, nil, time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for !c.LastActive().After(before) {
+		if time.Now().After(deadline) {
+			t.Fatal("pong did not count as activity")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestKickClosesConnection checks that a kicked member receives kicked,
+// leaves the room and has its connection closed with a policy violation
+func TestKickClosesConnection(t *testing.T) {
+	h := newTestHandler()
+	srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+	defer srv.Close()
+
+	host, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room"})
+	member, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room"})
+	var p model.Participant
+	json.Unmarshal(readUntil(t, host, model.MessageTypeNewClient).Payload, &p)
+
+	sendTestMessage(t, host, "kick", KickPayload{ClientID: p.ClientID, Reason: "spam"})
+	readUntil(t, member, model.MessageTypeKicked)
+	member.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var err error
+	for err == nil {
+		_, _, err = member.ReadMessage()
+	}
+	if !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+		t.Fatalf("got %v, want close 1008", err)
+	}
+	readUntil(t, host, model.MessageTypeLeaveClient)
+	if h.manager.IsMember(p.ClientID) {
+		t.Errorf("kicked client %s is still a member", p.ClientID)
+	}
+}
+
+// TestChatRelayAndHistory checks that a chat message reaches every member,
+// the sender included, and that later joiners get it in their chat history
+func TestChatRelayAndHistory(t *testing.T) {
+	h := newTestHandler()
+	srv := httptest.NewServer(http.HandlerFunc(h.CreateConnection))
+	defer srv.Close()
+
+	alice, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room", Name: "Alice"})
+	bob, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room", Name: "Bob"})
+
+	sendTestMessage(t, alice, "chat", ChatPayload{Text: "hello"})
+	var sent model.ChatMessage
+	for _, ws := range []*websocket.Conn{alice, bob} {
+		var chat model.ChatMessage
+		json.Unmarshal(readUntil(t, ws, model.MessageTypeChat).Payload, &chat)
+		if chat.Text != "hello" || chat.Name != "Alice" || chat.ID == "" {
+			t.Errorf("chat: got %+v, want hello from Alice", chat)
+		}
+		sent = chat
+	}
+
+	_, joined := joinAs(t, srv, JoinRoomPayload{RoomID: "room"})
+	var state struct {
+		ChatHistory []model.ChatMessage `json:"chat_history"`
+	}
+	json.Unmarshal(joined.Payload, &state)
+	if len(state.ChatHistory) != 1 || state.ChatHistory[0].ID != sent.ID || state.ChatHistory[0].Text != "hello" {
+		t.Errorf("chat history: got %+v, want the message of Alice", state.ChatHistory)
+	}
+
+	// Other rooms neither see the message nor its history
+	_, joined = joinAs(t, srv, JoinRoomPayload{RoomID: "other"})
+	json.Unmarshal(joined.Payload, &state)
+	if len(state.ChatHistory) != 0 {
+		t.Errorf("chat history of another room: got %+v, want none", state.ChatHistory)
+	}
+}
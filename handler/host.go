@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"gosignaling/config"
+	"gosignaling/manager"
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+const maxReasonLength = 256
+
+var (
+	errClientNotFound = errors.New("client not found")
+	errMissingMedia   = errors.New("audio or video is required")
+)
+
+// KickPayload represents the payload for removing a member from the room
+type KickPayload struct {
+	ClientID string `json:"client_id"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// MuteRequestPayload represents the payload for asking a member to mute
+type MuteRequestPayload struct {
+	ClientID string `json:"client_id"`
+	Audio    bool   `json:"audio"`
+	Video    bool   `json:"video"`
+}
+
+// LockRoomPayload represents the payload for locking or unlocking the room
+type LockRoomPayload struct {
+	Locked bool `json:"locked"`
+}
+
+// AdmissionPayload represents the payload for admitting or denying a client
+// waiting in the lobby
+type AdmissionPayload struct {
+	ClientID string `json:"client_id"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// BreakoutPayload represents the payload for splitting the room; it lists
+// the client IDs to move by breakout room name
+type BreakoutPayload struct {
+	Rooms map[string][]string `json:"rooms"`
+}
+
+// TransferHostPayload represents the payload for handing over the host role
+type TransferHostPayload struct {
+	ClientID string `json:"client_id"`
+}
+
+func (h *Handler) handleKick(c *model.Client, payload json.RawMessage) *model.Message {
+	var kickPayload KickPayload
+	if err := json.Unmarshal(payload, &kickPayload); err != nil {
+		log.Printf("Failed to unmarshal kick payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateClientID(kickPayload.ClientID); err != nil {
+		log.Printf("Rejected kick payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if len(kickPayload.Reason) > maxReasonLength {
+		return newErrorMessage("invalid payload", fmt.Errorf("reason exceeds %d characters", maxReasonLength))
+	}
+
+	if err := h.manager.Kick(c, kickPayload.ClientID, kickPayload.Reason); err != nil {
+		log.Printf("Failed to kick client: %v", err)
+		return newModerationError("failed to kick client", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) handleMuteRequest(c *model.Client, payload json.RawMessage) *model.Message {
+	var mutePayload MuteRequestPayload
+	if err := json.Unmarshal(payload, &mutePayload); err != nil {
+		log.Printf("Failed to unmarshal mute request payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateClientID(mutePayload.ClientID); err != nil {
+		log.Printf("Rejected mute request payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if !mutePayload.Audio && !mutePayload.Video {
+		return newErrorMessage("invalid payload", errMissingMedia)
+	}
+
+	if err := h.manager.RequestMute(c, mutePayload.ClientID, mutePayload.Audio, mutePayload.Video); err != nil {
+		log.Printf("Failed to request mute: %v", err)
+		return newModerationError("failed to request mute", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) handleLockRoom(c *model.Client, payload json.RawMessage) *model.Message {
+	var lockPayload LockRoomPayload
+	if err := json.Unmarshal(payload, &lockPayload); err != nil {
+		log.Printf("Failed to unmarshal lock room payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	if err := h.manager.LockRoom(c, lockPayload.Locked); err != nil {
+		log.Printf("Failed to lock room: %v", err)
+		return newModerationError("failed to lock room", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) handleTransferHost(c *model.Client, payload json.RawMessage) *model.Message {
+	var transferPayload TransferHostPayload
+	if err := json.Unmarshal(payload, &transferPayload); err != nil {
+		log.Printf("Failed to unmarshal transfer host payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateClientID(transferPayload.ClientID); err != nil {
+		log.Printf("Rejected transfer host payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	if err := h.manager.TransferHost(c, transferPayload.ClientID); err != nil {
+		log.Printf("Failed to transfer host: %v", err)
+		return newModerationError("failed to transfer host", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) handleAdmit(c *model.Client, payload json.RawMessage) *model.Message {
+	var admitPayload AdmissionPayload
+	if err := json.Unmarshal(payload, &admitPayload); err != nil {
+		log.Printf("Failed to unmarshal admit payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateClientID(admitPayload.ClientID); err != nil {
+		log.Printf("Rejected admit payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	if err := h.manager.Admit(c, admitPayload.ClientID); err != nil {
+		log.Printf("Failed to admit client: %v", err)
+		return newModerationError("failed to admit client", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) handleDeny(c *model.Client, payload json.RawMessage) *model.Message {
+	var denyPayload AdmissionPayload
+	if err := json.Unmarshal(payload, &denyPayload); err != nil {
+		log.Printf("Failed to unmarshal deny payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateClientID(denyPayload.ClientID); err != nil {
+		log.Printf("Rejected deny payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if len(denyPayload.Reason) > maxReasonLength {
+		return newErrorMessage("invalid payload", fmt.Errorf("reason exceeds %d characters", maxReasonLength))
+	}
+
+	if err := h.manager.Deny(c, denyPayload.ClientID, denyPayload.Reason); err != nil {
+		log.Printf("Failed to deny client: %v", err)
+		return newModerationError("failed to deny client", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) handleBreakout(c *model.Client, payload json.RawMessage) *model.Message {
+	var breakoutPayload BreakoutPayload
+	if err := json.Unmarshal(payload, &breakoutPayload); err != nil {
+		log.Printf("Failed to unmarshal breakout payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	if err := validateBreakout(&breakoutPayload); err != nil {
+		log.Printf("Rejected breakout payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	if err := h.manager.Breakout(c, breakoutPayload.Rooms); err != nil {
+		log.Printf("Failed to split room: %v", err)
+		return newModerationError("failed to split room", err)
+	}
+
+	return nil
+}
+
+func (h *Handler) handleRecall(c *model.Client) *model.Message {
+	if err := h.manager.Recall(c); err != nil {
+		log.Printf("Failed to recall breakout rooms: %v", err)
+		return newModerationError("failed to recall breakout rooms", err)
+	}
+	return nil
+}
+
+// newWaitingMessage tells a client that it waits in the lobby of a locked room
+func newWaitingMessage(roomID string) *model.Message {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"room_id":         roomID,
+		"timeout_seconds": int(config.Host.LobbyTimeout.Seconds()),
+	})
+	return &model.Message{
+		Type:    model.MessageTypeWaiting,
+		Payload: payload,
+	}
+}
+
+// newModerationError reports why a host action failed; only permission and
+// lookup errors are shown to the client
+func newModerationError(reason string, err error) *model.Message {
+	switch err {
+	case manager.ErrNotHost, manager.ErrSelfTarget, manager.ErrNestedBreakout:
+		return newErrorMessage(reason, err)
+	case repository.ErrNotFound:
+		return newErrorMessage(reason, errClientNotFound)
+	}
+	return newErrorMessage(reason, nil)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"gosignaling/model"
+)
+
+// UpdateProfilePayload represents the payload for changing a client's profile.
+// An empty name keeps the current one; metadata, when present, replaces the
+// current metadata.
+type UpdateProfilePayload struct {
+	Name     string            `json:"name,omitempty"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func (h *Handler) handleUpdateProfile(c *model.Client, payload json.RawMessage) *model.Message {
+	var profilePayload UpdateProfilePayload
+	if err := json.Unmarshal(payload, &profilePayload); err != nil {
+		log.Printf("Failed to unmarshal update profile payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+	profilePayload.Name = strings.TrimSpace(profilePayload.Name)
+	if profilePayload.Name == "" && profilePayload.Metadata == nil {
+		return newErrorMessage("invalid payload", errEmptyProfile)
+	}
+	if err := validateProfile(profilePayload.Name, profilePayload.Metadata); err != nil {
+		log.Printf("Rejected update profile payload from %s: %v", c.ID, err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	profile := mergeProfile(c.Profile(), profilePayload.Name, profilePayload.Metadata)
+	if err := h.manager.UpdateProfile(c, profile); err != nil {
+		log.Printf("Failed to update profile: %v", err)
+		return newErrorMessage("failed to update profile", nil)
+	}
+
+	return nil
+}
+
+// mergeProfile applies a requested name and metadata to a profile
+func mergeProfile(p model.Profile, name string, metadata map[string]string) model.Profile {
+	if name = strings.TrimSpace(name); name != "" {
+		p.Name = name
+	}
+	if metadata != nil {
+		p.Metadata = metadata
+		if len(metadata) == 0 {
+			p.Metadata = nil
+		}
+	}
+	return p
+}
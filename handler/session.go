@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gosignaling/manager"
+	"gosignaling/model"
+)
+
+const maxSessionBodySize = 64 * 1024
+
+var (
+	errNoPeer        = errors.New("no peer available in room")
+	errAmbiguousPeer = errors.New("room has several members; choose one with the query parameter")
+	errAnswerTimeout = errors.New("timed out waiting for an answer")
+	errSessionClosed = errors.New("session closed")
+)
+
+// sessionKind describes an HTTP signaling protocol (WHIP or WHEP) that is
+// mapped onto a virtual client exchanging SDP with one room member
+type sessionKind struct {
+	name      string // protocol name used in logs and paths, e.g. "whip" or "whep"
+	peerParam string // query parameter naming the room member to talk to
+}
+
+// httpSession is an HTTP signaling session backed by a virtual client. The
+// session ID in its resource URL authorizes PATCH and DELETE; the client ID
+// is shared with the room and only used for signaling.
+type httpSession struct {
+	id     string
+	kind   sessionKind
+	client *model.Client
+	roomID string
+	peerID string
+	ip     string
+
+	answers   chan string
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// serveSession routes the requests of an HTTP signaling protocol:
+//
+//	POST   /<kind>/{room}           SDP offer, answered with 201 and the SDP answer
+//	PATCH  /<kind>/{room}/{session} trickle ICE (application/trickle-ice-sdpfrag)
+//	DELETE /<kind>/{room}/{session} teardown
+func (h *Handler) serveSession(kind sessionKind, w http.ResponseWriter, r *http.Request) {
+	setSessionCORSHeaders(w)
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/"+kind.name), "/"), "/")
+	switch {
+	case r.Method == http.MethodOptions:
+		w.Header().Set("Accept-Post", "application/sdp")
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 1 && parts[0] != "" && r.Method == http.MethodPost:
+		h.createSession(kind, w, r, parts[0])
+	case len(parts) == 2 && (r.Method == http.MethodPatch || r.Method == http.MethodDelete):
+		s := h.getSession(kind, parts[0], parts[1])
+		if s == nil {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPatch {
+			h.trickleSession(s, w, r)
+		} else {
+			h.endSession(s)
+			w.WriteHeader(http.StatusOK)
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// createSession joins a virtual client to the room, relays the offer to the
+// chosen peer and responds with its answer
+func (h *Handler) createSession(kind sessionKind, w http.ResponseWriter, r *http.Request, roomID string) {
+	if ct := r.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/sdp") {
+		http.Error(w, "content type must be application/sdp", http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSessionBodySize))
+	if err != nil {
+		http.Error(w, "failed to read offer", http.StatusBadRequest)
+		return
+	}
+	offer := string(body)
+	if err := validateSDP(offer); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateJoinRoom(&JoinRoomPayload{RoomID: roomID}); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	peerID, err := h.choosePeer(roomID, r.URL.Query().Get(kind.peerParam))
+	if err != nil {
+		if err == errAmbiguousPeer {
+			err = fmt.Errorf("%v ?%s=<client_id>", err, kind.peerParam)
+		}
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	ip := clientIP(r)
+	if err := h.rateLimiter.AcquireConnection(ip); err != nil {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	s := &httpSession{
+		id:      newSessionID(),
+		kind:    kind,
+		client:  model.NewVirtualClient(kind.name),
+		roomID:  roomID,
+		peerID:  peerID,
+		ip:      ip,
+		answers: make(chan string, 1),
+		done:    make(chan struct{}),
+	}
+	// WHIP and WHEP clients send the password of a persistent room as their bearer token
+	if err := h.manager.JoinRoom(s.client, roomID, bearerToken(r), false); err != nil {
+		h.rateLimiter.ReleaseConnection(ip)
+		if err == manager.ErrWrongPassword {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err == manager.ErrBreakoutRoom {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, "failed to join room", http.StatusInternalServerError)
+		return
+	}
+	h.addSession(s)
+	go h.pumpSession(s)
+
+	sdp := &model.SDP{Type: "offer", SDP: offer}
+	if err := h.manager.TransferSDPOffer(s.client, sdp, peerID); err != nil {
+		h.endSession(s)
+		http.Error(w, "failed to transfer offer", http.StatusBadGateway)
+		return
+	}
+
+	answer, err := s.waitForAnswer(h.signaling.AnswerTimeout)
+	if err != nil {
+		h.endSession(s)
+		http.Error(w, err.Error(), http.StatusGatewayTimeout)
+		return
+	}
+
+	for _, server := range h.iceServers.IceServers(s.client.ID, r.Host) {
+		for _, url := range server.URLs {
+			w.Header().Add("Link", iceServerLink(url, server))
+		}
+	}
+	w.Header().Set("Content-Type", "application/sdp")
+	w.Header().Set("Location", fmt.Sprintf("/%s/%s/%s", kind.name, roomID, s.id))
+	w.Header().Set("ETag", strconv.Quote(s.id))
+	w.WriteHeader(http.StatusCreated)
+	io.WriteString(w, answer)
+
+	log.Printf("%s session %s started in room %s with peer %s", strings.ToUpper(kind.name), s.client.ID, roomID, peerID)
+}
+
+// choosePeer returns the requested peer, or the only non-virtual member of the room
+func (h *Handler) choosePeer(roomID, requested string) (string, error) {
+	if requested != "" {
+		return requested, validateClientID(requested)
+	}
+	clients, err := h.manager.GetClients(roomID)
+	if err != nil {
+		return "", errNoPeer
+	}
+	var peerID string
+	for _, c := range clients {
+		if c.Virtual {
+			continue
+		}
+		if peerID != "" {
+			return "", errAmbiguousPeer
+		}
+		peerID = c.ID
+	}
+	if peerID == "" {
+		return "", errNoPeer
+	}
+	return peerID, nil
+}
+
+// trickleSession forwards the candidates of a trickle-ice-sdpfrag body to the peer
+func (h *Handler) trickleSession(s *httpSession, w http.ResponseWriter, r *http.Request) {
+	if ct := r.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/trickle-ice-sdpfrag") {
+		http.Error(w, "content type must be application/trickle-ice-sdpfrag", http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSessionBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	candidates, err := parseSDPFrag(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, candidate := range candidates {
+		if err := h.manager.TransferIceCandidate(s.client, candidate, s.peerID); err != nil {
+			log.Printf("Failed to transfer ICE candidate for %s session %s: %v", s.kind.name, s.client.ID, err)
+			http.Error(w, "failed to transfer ice candidate", http.StatusBadGateway)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pumpSession consumes the messages addressed to the virtual client
+func (h *Handler) pumpSession(s *httpSession) {
+	for {
+		select {
+		case msg := <-s.client.Send:
+			var payload struct {
+				ClientID string `json:"client_id"`
+				SDP      string `json:"sdp"`
+			}
+			json.Unmarshal(msg.Payload, &payload)
+			if msg.Disconnects() {
+				log.Printf("%s session %s ended by %s", strings.ToUpper(s.kind.name), s.client.ID, msg.Type)
+				h.endSession(s)
+				return
+			}
+			if payload.ClientID != s.peerID {
+				continue
+			}
+
+			switch msg.Type {
+			case model.MessageTypeSDPAnswer:
+				select {
+				case s.answers <- payload.SDP:
+				default:
+				}
+			case model.MessageTypeLeaveClient:
+				log.Printf("Peer %s left, ending %s session %s", s.peerID, s.kind.name, s.client.ID)
+				h.endSession(s)
+				return
+			}
+		case <-s.client.Disconnected():
+			log.Printf("%s session %s ended by %s", strings.ToUpper(s.kind.name), s.client.ID, s.client.Farewell().Type)
+			h.endSession(s)
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// endSession removes the virtual client from its room and forgets the session
+func (h *Handler) endSession(s *httpSession) {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		h.removeSession(s)
+		h.manager.LeaveRoom(s.client)
+		h.rateLimiter.ReleaseConnection(s.ip)
+		log.Printf("%s session %s ended", strings.ToUpper(s.kind.name), s.client.ID)
+	})
+}
+
+func (s *httpSession) waitForAnswer(timeout time.Duration) (string, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case answer := <-s.answers:
+		if err := validateSDP(answer); err != nil {
+			return "", err
+		}
+		return answer, nil
+	case <-s.done:
+		return "", errSessionClosed
+	case <-timer.C:
+		return "", errAnswerTimeout
+	}
+}
+
+func (h *Handler) addSession(s *httpSession) {
+	h.sessionsMutex.Lock()
+	defer h.sessionsMutex.Unlock()
+	h.sessions[s.id] = s
+}
+
+func (h *Handler) removeSession(s *httpSession) {
+	h.sessionsMutex.Lock()
+	defer h.sessionsMutex.Unlock()
+	delete(h.sessions, s.id)
+}
+
+func (h *Handler) getSession(kind sessionKind, roomID, sessionID string) *httpSession {
+	h.sessionsMutex.Lock()
+	defer h.sessionsMutex.Unlock()
+
+	s, ok := h.sessions[sessionID]
+	if !ok || s.kind != kind || s.roomID != roomID {
+		return nil
+	}
+	return s
+}
+
+// parseSDPFrag extracts the candidates of a trickle-ice-sdpfrag body (RFC 8840)
+func parseSDPFrag(frag string) ([]*model.IceCandidate, error) {
+	var (
+		candidates []*model.IceCandidate
+		mid        *string
+		mLineIndex *uint16
+		mLines     uint16
+	)
+	for _, line := range strings.Split(frag, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "m="):
+			index := mLines
+			mLineIndex = &index
+			mid = nil
+			mLines++
+		case strings.HasPrefix(line, "a=mid:"):
+			value := strings.TrimPrefix(line, "a=mid:")
+			mid = &value
+		case strings.HasPrefix(line, "a=candidate:"):
+			candidate := strings.TrimPrefix(line, "a=")
+			if err := validateIceCandidate(candidate); err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, &model.IceCandidate{
+				Candidate:     candidate,
+				SdpMid:        mid,
+				SdpMLineIndex: mLineIndex,
+			})
+		}
+	}
+	return candidates, nil
+}
+
+// iceServerLink formats an ICE server as a Link header (RFC 9725 section 4.6)
+func iceServerLink(url string, server model.IceServer) string {
+	link := fmt.Sprintf("<%s>; rel=\"ice-server\"", url)
+	if server.Username != "" {
+		link += fmt.Sprintf("; username=%q; credential=%q; credential-type=\"password\"", server.Username, server.Credential)
+	}
+	return link
+}
+
+func setSessionCORSHeaders(w http.ResponseWriter) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, PATCH, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+	w.Header().Set("Access-Control-Expose-Headers", "Location, ETag, Link")
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gosignaling/model"
+)
+
+// newSessionTestServer serves WebSocket connections on / and the WHIP and
+// WHEP endpoints of h
+func newSessionTestServer(t *testing.T, h *Handler) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", h.CreateConnection)
+	mux.HandleFunc("/whip/", h.HandleWHIP)
+	mux.HandleFunc("/whep/", h.HandleWHEP)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func sessionRequest(t *testing.T, method, url, contentType, body string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// testSession runs a WHIP or WHEP session against a WebSocket member of the
+// room: the offer is answered by the member, trickled candidates reach it,
+// and DELETE ends the session
+func testSession(t *testing.T, kind sessionKind) {
+	h := newTestHandler()
+	srv := newSessionTestServer(t, h)
+	peer, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room"})
+
+	// The POST waits for the member to answer the offer of the virtual client
+	posted := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.Post(srv.URL+"/"+kind.name+"/room", "application/sdp", strings.NewReader(testSDP))
+		if err != nil {
+			t.Errorf("POST: %v", err)
+		}
+		posted <- resp
+	}()
+	var offer SDPOfferPayload
+	json.Unmarshal(readUntil(t, peer, model.MessageTypeSDPOffer).Payload, &offer)
+	sendTestMessage(t, peer, "answer", SDPAnswerPayload{SDP: testSDP, ClientID: offer.ClientID})
+	resp := <-posted
+	if resp == nil {
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+	virtualID := offer.ClientID
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusCreated || string(body) != testSDP {
+		t.Fatalf("offer: got %d %q, want 201 with the answer", resp.StatusCode, body)
+	}
+
+	// The session resource is not named after the client ID the room sees
+	location := resp.Header.Get("Location")
+	sessionID := location[strings.LastIndex(location, "/")+1:]
+	if !strings.HasPrefix(location, "/"+kind.name+"/room/") || sessionID == virtualID {
+		t.Fatalf("Location %q must name a session ID other than client ID %s", location, virtualID)
+	}
+	if etag := resp.Header.Get("ETag"); etag != `"`+sessionID+`"` {
+		t.Errorf("ETag %s, want the session ID", etag)
+	}
+	if resp := sessionRequest(t, http.MethodDelete, srv.URL+"/"+kind.name+"/room/"+virtualID, "", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("DELETE by client ID: got %d, want 404", resp.StatusCode)
+	}
+
+	frag := "a=ice-ufrag:Vx3G\r\na=ice-pwd:9Jv3sVmGLbkbq4nBXhPQqjbo\r\nm=audio 9 UDP/TLS/RTP/SAVPF 111\r\na=mid:0\r\n" +
+		"a=candidate:842163049 1 udp 2122260223 192.168.1.2 56143 typ host generation 0\r\n"
+	if resp := sessionRequest(t, http.MethodPatch, srv.URL+location, "application/trickle-ice-sdpfrag", frag); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("PATCH: got %d, want 204", resp.StatusCode)
+	}
+	var candidate IceCandidatePayload
+	json.Unmarshal(readUntil(t, peer, model.MessageTypeIceCandidate).Payload, &candidate)
+	if candidate.ClientID != virtualID || !strings.HasPrefix(candidate.Candidate, "candidate:842163049") ||
+		candidate.SdpMid == nil || *candidate.SdpMid != "0" {
+		t.Errorf("trickled candidate: got %+v", candidate)
+	}
+
+	if resp := sessionRequest(t, http.MethodDelete, srv.URL+location, "", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("DELETE: got %d, want 200", resp.StatusCode)
+	}
+	var left model.Participant
+	json.Unmarshal(readUntil(t, peer, model.MessageTypeLeaveClient).Payload, &left)
+	if left.ClientID != virtualID {
+		t.Errorf("leave-client for %s, want %s", left.ClientID, virtualID)
+	}
+	if resp := sessionRequest(t, http.MethodPatch, srv.URL+location, "application/trickle-ice-sdpfrag", frag); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("PATCH after DELETE: got %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestWHIPSession(t *testing.T) {
+	testSession(t, whip)
+}
+
+func TestWHEPSession(t *testing.T) {
+	testSession(t, whep)
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"gosignaling/model"
+	"gosignaling/services"
+)
+
+var errRateLimited = errors.New("rate-limited")
+
+// SSERelay hands the messages POSTed for SSE sessions that this pod does not
+// have to the other pods; see ReceiveSSEMessage
+type SSERelay interface {
+	PublishSSEMessage(sessionID string, msgBytes []byte) error
+}
+
+// sseSession is a client connected through the Server-Sent Events fallback
+// transport: it receives messages on an event stream and sends them with
+// HTTP POST requests carrying the session ID.
+type sseSession struct {
+	id      string
+	client  *model.Client
+	limiter *services.ClientLimiter
+
+	// mutex serializes the POSTed messages and the departure of the client,
+	// so a join cannot be processed after the stream has left the room
+	mutex     sync.Mutex
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// close ends the event stream of the session
+func (s *sseSession) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// HandleSSE serves the fallback transport for networks that block WebSocket upgrades:
+//
+//	GET  /sse              event stream; the first event is notify-client-id with a session_id
+//	POST /sse/{session_id} sends one signaling message, with the same JSON as on the WebSocket
+func (h *Handler) HandleSSE(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	sessionID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/sse"), "/")
+	switch {
+	case sessionID == "" && r.Method == http.MethodGet:
+		h.streamEvents(w, r)
+	case sessionID != "" && r.Method == http.MethodPost:
+		h.receiveSSEMessage(w, r, sessionID)
+	case r.Method == http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// streamEvents writes the messages of a new client as Server-Sent Events until
+// the request ends. Like HandleSendMessage, it is the only writer of its stream.
+func (h *Handler) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ip := clientIP(r)
+	if err := h.rateLimiter.AcquireConnection(ip); err != nil {
+		log.Printf("Rejected SSE connection from %s: %v", ip, err)
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	defer h.rateLimiter.ReleaseConnection(ip)
+
+	s := &sseSession{
+		id:      newSessionID(),
+		client:  model.NewClient("user"),
+		limiter: h.rateLimiter.NewClientLimiter(ip),
+		done:    make(chan struct{}),
+	}
+	h.addSSESession(s)
+	h.addLiveClient(s.client.ID)
+	defer func() {
+		s.close()
+		h.removeSSESession(s)
+		h.removeLiveClient(s.client.ID)
+		s.mutex.Lock()
+		h.manager.LeaveRoom(s.client)
+		s.mutex.Unlock()
+		log.Printf("SSE client disconnected: %s", s.client.ID)
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Send client ID, ICE servers and the session ID used for POSTs
+	s.client.Send <- h.newNotifyClientIDMessage(s.client, r, map[string]interface{}{"session_id": s.id})
+	log.Printf("New SSE client connected: %s", s.client.ID)
+
+	ticker := time.NewTicker(h.signaling.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-s.client.Send:
+			msgBytes, err := json.Marshal(msg)
+			if err != nil {
+				log.Printf("Failed to marshal message: %v", err)
+				return
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", msgBytes); err != nil {
+				return
+			}
+			flusher.Flush()
+			if msg.Disconnects() {
+				return
+			}
+		case <-s.client.Disconnected():
+			if msgBytes, err := json.Marshal(s.client.Farewell()); err == nil {
+				fmt.Fprintf(w, "data: %s\n\n", msgBytes)
+				flusher.Flush()
+			}
+			return
+		case <-ticker.C:
+			// Comment lines keep proxies from closing an idle stream
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-s.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// receiveSSEMessage processes a message POSTed for an SSE session; any
+// response is delivered on the event stream, as on the WebSocket. A session
+// of another pod is handed to it through the SSE relay.
+func (h *Handler) receiveSSEMessage(w http.ResponseWriter, r *http.Request, sessionID string) {
+	s := h.getSSESession(sessionID)
+	if s == nil && h.sseRelay == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	msgBytes, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.signaling.MaxMessageSize))
+	if err != nil {
+		http.Error(w, "message too big", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if s == nil {
+		// Pods only know their own streams; the one with the session processes it
+		if err := h.sseRelay.PublishSSEMessage(sessionID, msgBytes); err != nil {
+			log.Printf("Failed to relay message for SSE session: %v", err)
+			http.Error(w, "failed to relay message", http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	switch err := h.processSSEMessage(s, msgBytes); err {
+	case nil:
+		w.WriteHeader(http.StatusAccepted)
+	case errSessionClosed:
+		http.Error(w, "session not found", http.StatusNotFound)
+	case errRateLimited:
+		http.Error(w, "rate-limited", http.StatusTooManyRequests)
+	}
+}
+
+// ReceiveSSEMessage processes a message that was POSTed on another pod for
+// an SSE session of this one. Messages for unknown sessions are ignored,
+// since every pod receives them.
+func (h *Handler) ReceiveSSEMessage(sessionID string, msgBytes []byte) {
+	if s := h.getSSESession(sessionID); s != nil {
+		h.processSSEMessage(s, msgBytes)
+	}
+}
+
+// processSSEMessage processes a message of session s. It fails with
+// errSessionClosed once the stream has ended, and with errRateLimited when
+// the client was disconnected for exceeding the rate limits.
+func (h *Handler) processSSEMessage(s *sseSession, msgBytes []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	select {
+	case <-s.done:
+		return errSessionClosed
+	default:
+	}
+	s.client.Touch()
+
+	resp, ok := h.processMessage(s.client, s.limiter, msgBytes)
+	if !ok {
+		log.Printf("Disconnecting SSE client %s for exceeding rate limits", s.client.ID)
+		s.close()
+		return errRateLimited
+	}
+	if resp != nil {
+		select {
+		case s.client.Send <- resp:
+		case <-s.done:
+		}
+	}
+	return nil
+}
+
+// SetSSERelay lets POSTs reach the SSE sessions of other pods; without a
+// relay they are answered with 404 Not Found. Call it before serving.
+func (h *Handler) SetSSERelay(relay SSERelay) {
+	h.sseRelay = relay
+}
+
+func (h *Handler) addSSESession(s *sseSession) {
+	h.sessionsMutex.Lock()
+	defer h.sessionsMutex.Unlock()
+	h.sseSessions[s.id] = s
+}
+
+func (h *Handler) removeSSESession(s *sseSession) {
+	h.sessionsMutex.Lock()
+	defer h.sessionsMutex.Unlock()
+	delete(h.sseSessions, s.id)
+}
+
+func (h *Handler) getSSESession(sessionID string) *sseSession {
+	h.sessionsMutex.Lock()
+	defer h.sessionsMutex.Unlock()
+	return h.sseSessions[sessionID]
+}
+
+// newSessionID returns an unguessable session ID; client IDs are shared with
+// other room members and therefore cannot authenticate a sender
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gosignaling/model"
+)
+
+// sseStream is an open event stream of a test client
+type sseStream struct {
+	t         *testing.T
+	events    *bufio.Reader
+	sessionID string
+	clientID  string
+	close     func()
+}
+
+// openSSE opens an event stream on srv and reads its notify-client-id event
+func openSSE(t *testing.T, srv *httptest.Server) *sseStream {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/sse", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		t.Fatalf("GET /sse: %v", err)
+	}
+	s := &sseStream{t: t, events: bufio.NewReader(resp.Body), close: func() {
+		cancel()
+		resp.Body.Close()
+	}}
+	t.Cleanup(s.close)
+
+	var notify struct {
+		ClientID  string `json:"client_id"`
+		SessionID string `json:"session_id"`
+	}
+	json.Unmarshal(s.readUntil(model.MessageTypeNotifyClientID).Payload, &notify)
+	s.sessionID, s.clientID = notify.SessionID, notify.ClientID
+	return s
+}
+
+// readUntil reads events until a message of type msgType arrives
+func (s *sseStream) readUntil(msgType model.MessageType) *model.Message {
+	s.t.Helper()
+	for {
+		line, err := s.events.ReadString('\n')
+		if err != nil {
+			s.t.Fatalf("waiting for %s: %v", msgType, err)
+		}
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+		if !ok {
+			continue
+		}
+		var msg model.Message
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			s.t.Fatalf("invalid event %q: %v", data, err)
+		}
+		if msg.Type == msgType {
+			return &msg
+		}
+	}
+}
+
+// post sends a message for the session of s and returns the status code
+func (s *sseStream) post(srv *httptest.Server, msgType string, payload interface{}) int {
+	s.t.Helper()
+	return postSSE(s.t, srv, s.sessionID, msgType, payload)
+}
+
+func postSSE(t *testing.T, srv *httptest.Server, sessionID, msgType string, payload interface{}) int {
+	t.Helper()
+	data, _ := json.Marshal(payload)
+	msg, _ := json.Marshal(ReceiveMessage{Type: msgType, Payload: data})
+	resp, err := http.Post(srv.URL+"/sse/"+sessionID, "application/json", strings.NewReader(string(msg)))
+	if err != nil {
+		t.Fatalf("POST %s: %v", msgType, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func newSSETestServer(t *testing.T, h *Handler) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", h.CreateConnection)
+	mux.HandleFunc("/sse", h.HandleSSE)
+	mux.HandleFunc("/sse/", h.HandleSSE)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestSSESession joins a room over SSE, meets a WebSocket member, and
+// reconnects with a new session after the stream closed
+func TestSSESession(t *testing.T) {
+	h := newTestHandler()
+	srv := newSSETestServer(t, h)
+
+	stream := openSSE(t, srv)
+	if stream.sessionID == "" || stream.sessionID == stream.clientID {
+		t.Fatalf("session ID %q must be set and differ from the client ID", stream.sessionID)
+	}
+	if code := stream.post(srv, "join", JoinRoomPayload{RoomID: "room"}); code != http.StatusAccepted {
+		t.Fatalf("POST join: got %d, want 202", code)
+	}
+	stream.readUntil(model.MessageTypeJoined)
+
+	peer, _ := joinAs(t, srv, JoinRoomPayload{RoomID: "room", Name: "Bob"})
+	var p model.Participant
+	json.Unmarshal(stream.readUntil(model.MessageTypeNewClient).Payload, &p)
+	if p.Name != "Bob" {
+		t.Errorf("new-client name: got %q, want Bob", p.Name)
+	}
+	// Replies to POSTs arrive on the stream
+	stream.post(srv, "join", JoinRoomPayload{})
+	stream.readUntil(model.MessageTypeError)
+
+	// Closing the stream leaves the room and ends the session
+	stream.close()
+	json.Unmarshal(readUntil(t, peer, model.MessageTypeLeaveClient).Payload, &p)
+	if p.ClientID != stream.clientID {
+		t.Errorf("leave-client for %s, want %s", p.ClientID, stream.clientID)
+	}
+	waitDisconnected(t, h, stream.clientID)
+	if code := stream.post(srv, "join", JoinRoomPayload{RoomID: "room"}); code != http.StatusNotFound {
+		t.Errorf("POST after the stream closed: got %d, want 404", code)
+	}
+
+	again := openSSE(t, srv)
+	if again.sessionID == stream.sessionID {
+		t.Error("a new stream reused the session ID")
+	}
+	again.post(srv, "join", JoinRoomPayload{RoomID: "room"})
+	var state struct {
+		Participants []model.Participant `json:"participants"`
+	}
+	json.Unmarshal(again.readUntil(model.MessageTypeJoined).Payload, &state)
+	if len(state.Participants) != 1 || state.Participants[0].Name != "Bob" {
+		t.Errorf("participants after reconnecting: got %+v, want Bob", state.Participants)
+	}
+}
+
+// fakeSSERelay records the messages handed to other pods
+type fakeSSERelay struct {
+	mutex     sync.Mutex
+	published []string
+}
+
+func (f *fakeSSERelay) PublishSSEMessage(sessionID string, msgBytes []byte) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.published = append(f.published, sessionID)
+	return nil
+}
+
+// TestSSERelay checks that POSTs for sessions of other pods are relayed, and
+// that messages relayed by other pods reach the local session
+func TestSSERelay(t *testing.T) {
+	h := newTestHandler()
+	relay := &fakeSSERelay{}
+	h.SetSSERelay(relay)
+	srv := newSSETestServer(t, h)
+
+	if code := postSSE(t, srv, "elsewhere", "join", JoinRoomPayload{RoomID: "room"}); code != http.StatusAccepted {
+		t.Errorf("POST for another pod: got %d, want 202", code)
+	}
+	if len(relay.published) != 1 || relay.published[0] != "elsewhere" {
+		t.Errorf("relayed sessions: got %q, want elsewhere", relay.published)
+	}
+
+	stream := openSSE(t, srv)
+	msg, _ := json.Marshal(ReceiveMessage{Type: "join", Payload: json.RawMessage(`{"room_id":"room"}`)})
+	h.ReceiveSSEMessage(stream.sessionID, msg)
+	stream.readUntil(model.MessageTypeJoined)
+	if len(relay.published) != 1 {
+		t.Errorf("a POST for a local session was relayed")
+	}
+}
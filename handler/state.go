@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	"gosignaling/model"
+)
+
+var errEmptyState = errors.New("at least one state field is required")
+
+// StateUpdatePayload represents the payload for changing a client's media
+// state. Omitted fields keep their current value.
+type StateUpdatePayload struct {
+	AudioMuted    *bool `json:"audio_muted,omitempty"`
+	VideoMuted    *bool `json:"video_muted,omitempty"`
+	ScreenSharing *bool `json:"screen_sharing,omitempty"`
+	Speaking      *bool `json:"speaking,omitempty"`
+	HandRaised    *bool `json:"hand_raised,omitempty"`
+}
+
+func (h *Handler) handleStateUpdate(c *model.Client, payload json.RawMessage) *model.Message {
+	var statePayload StateUpdatePayload
+	if err := json.Unmarshal(payload, &statePayload); err != nil {
+		log.Printf("Failed to unmarshal state update payload: %v", err)
+		return newErrorMessage("invalid payload", err)
+	}
+
+	current := c.State()
+	state, changed := statePayload.apply(current)
+	if !changed {
+		return newErrorMessage("invalid payload", errEmptyState)
+	}
+	// Repeated updates, e.g. of speaking, are not worth a broadcast
+	if state == current {
+		return nil
+	}
+
+	if err := h.manager.UpdateState(c, state); err != nil {
+		log.Printf("Failed to update state: %v", err)
+		return newErrorMessage("failed to update state", nil)
+	}
+
+	return nil
+}
+
+// apply sets the fields present in the payload on a state and reports
+// whether the payload had any
+func (p *StateUpdatePayload) apply(s model.MediaState) (model.MediaState, bool) {
+	changed := false
+	for _, f := range []struct {
+		value *bool
+		field *bool
+	}{
+		{p.AudioMuted, &s.AudioMuted},
+		{p.VideoMuted, &s.VideoMuted},
+		{p.ScreenSharing, &s.ScreenSharing},
+		{p.Speaking, &s.Speaking},
+		{p.HandRaised, &s.HandRaised},
+	} {
+		if f.value != nil {
+			*f.field = *f.value
+			changed = true
+		}
+	}
+	return s, changed
+}
@@ -0,0 +1,404 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gosignaling/config"
+	"gosignaling/model"
+)
+
+const (
+	maxRoomIDLength    = 128
+	maxClientIDLength  = 64
+	maxCandidateLength = 1024
+	maxSdpMidLength    = 64
+	maxMessageIDLength = 64
+	maxNameLength      = 64
+	maxMetadataEntries = 16
+	maxMetadataKey     = 64
+	maxMetadataValue   = 512
+	maxBreakoutRooms   = 50
+	maxBreakoutName    = 32
+	maxRoomCapacity    = 1000
+	maxPasswordLength  = 128
+)
+
+var (
+	errMissingRoomID   = errors.New("room_id is required")
+	errMissingClientID = errors.New("client_id is required")
+	errMissingSDP      = errors.New("sdp is required")
+	errMissingData     = errors.New("data is required")
+	errMissingText     = errors.New("text is required")
+	errMissingMsgID    = errors.New("message_id is required")
+	errEmptyProfile    = errors.New("name or metadata is required")
+)
+
+// validateJoinRoom checks a join payload
+func validateJoinRoom(p *JoinRoomPayload) error {
+	if p.RoomID == "" {
+		return errMissingRoomID
+	}
+	if len(p.RoomID) > maxRoomIDLength {
+		return fmt.Errorf("room_id exceeds %d characters", maxRoomIDLength)
+	}
+	for _, r := range p.RoomID {
+		if r < 0x20 || r == 0x7f {
+			return errors.New("room_id contains control characters")
+		}
+	}
+	return validateProfile(p.Name, p.Metadata)
+}
+
+// validateRoomConfig checks the settings of a persistent room
+func validateRoomConfig(p *RoomConfigPayload) error {
+	if p.Capacity < 0 || p.Capacity > maxRoomCapacity {
+		return fmt.Errorf("capacity must be 0 to %d", maxRoomCapacity)
+	}
+	if len(p.Password) > maxPasswordLength {
+		return fmt.Errorf("password exceeds %d characters", maxPasswordLength)
+	}
+	if p.Topology != model.TopologyMesh && p.Topology != model.TopologySFU {
+		return fmt.Errorf("topology must be %q or %q", model.TopologyMesh, model.TopologySFU)
+	}
+	if p.LifetimeSeconds < 0 || p.MaxDurationSeconds < 0 {
+		return errors.New("lifetime_seconds and max_duration_seconds cannot be negative")
+	}
+	if p.ExpiresAt != nil {
+		if p.LifetimeSeconds > 0 {
+			return errors.New("use either expires_at or lifetime_seconds")
+		}
+		if !p.ExpiresAt.After(time.Now()) {
+			return errors.New("expires_at must be in the future")
+		}
+	}
+	return nil
+}
+
+// validateBreakout checks the room names and client IDs of a breakout payload
+func validateBreakout(p *BreakoutPayload) error {
+	if len(p.Rooms) == 0 {
+		return errors.New("rooms is required")
+	}
+	if len(p.Rooms) > maxBreakoutRooms {
+		return fmt.Errorf("rooms exceeds %d entries", maxBreakoutRooms)
+	}
+	for name, clientIDs := range p.Rooms {
+		if name == "" || len(name) > maxBreakoutName {
+			return fmt.Errorf("breakout room names must be 1 to %d characters", maxBreakoutName)
+		}
+		if strings.Contains(name, "/") {
+			return errors.New("breakout room names cannot contain /")
+		}
+		for _, r := range name {
+			if r < 0x20 || r == 0x7f {
+				return errors.New("breakout room name contains control characters")
+			}
+		}
+		for _, clientID := range clientIDs {
+			if err := validateClientID(clientID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateProfile checks a display name and metadata; both may be empty
+func validateProfile(name string, metadata map[string]string) error {
+	if len(name) > maxNameLength {
+		return fmt.Errorf("name exceeds %d characters", maxNameLength)
+	}
+	if !utf8.ValidString(name) {
+		return errors.New("name is not valid UTF-8")
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return errors.New("name contains control characters")
+		}
+	}
+	if len(metadata) > maxMetadataEntries {
+		return fmt.Errorf("metadata exceeds %d entries", maxMetadataEntries)
+	}
+	for k, v := range metadata {
+		if k == "" || len(k) > maxMetadataKey {
+			return fmt.Errorf("metadata keys must be 1 to %d characters", maxMetadataKey)
+		}
+		if len(v) > maxMetadataValue {
+			return fmt.Errorf("metadata value of %q exceeds %d characters", k, maxMetadataValue)
+		}
+	}
+	return nil
+}
+
+// validateClientID checks a target client ID
+func validateClientID(clientID string) error {
+	if clientID == "" {
+		return errMissingClientID
+	}
+	if len(clientID) > maxClientIDLength {
+		return fmt.Errorf("client_id exceeds %d characters", maxClientIDLength)
+	}
+	return nil
+}
+
+// validateSDPPayload checks an offer or answer payload
+func validateSDPPayload(sdp, clientID string) error {
+	if err := validateClientID(clientID); err != nil {
+		return err
+	}
+	return validateSDP(sdp)
+}
+
+// validateIceCandidatePayload checks an ice-candidate payload
+func validateIceCandidatePayload(p *IceCandidatePayload) error {
+	if err := validateClientID(p.ClientID); err != nil {
+		return err
+	}
+	if p.SdpMid != nil && len(*p.SdpMid) > maxSdpMidLength {
+		return fmt.Errorf("sdpMid exceeds %d characters", maxSdpMidLength)
+	}
+	return validateIceCandidate(p.Candidate)
+}
+
+// validateAppData checks the opaque data of a broadcast or direct message
+func validateAppData(data []byte) error {
+	if len(data) == 0 || string(data) == "null" {
+		return errMissingData
+	}
+	if len(data) > config.AppMessages.MaxDataSize {
+		return fmt.Errorf("data exceeds %d bytes", config.AppMessages.MaxDataSize)
+	}
+	return nil
+}
+
+// validateChatText checks the text of a chat message
+func validateChatText(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return errMissingText
+	}
+	if len(text) > config.Chat.MaxLength {
+		return fmt.Errorf("text exceeds %d bytes", config.Chat.MaxLength)
+	}
+	if !utf8.ValidString(text) {
+		return errors.New("text is not valid UTF-8")
+	}
+	return nil
+}
+
+// validateMessageID checks the ID of a chat message
+func validateMessageID(messageID string) error {
+	if messageID == "" {
+		return errMissingMsgID
+	}
+	if len(messageID) > maxMessageIDLength {
+		return fmt.Errorf("message_id exceeds %d characters", maxMessageIDLength)
+	}
+	return nil
+}
+
+// validateSDP performs a structural sanity check of a session description
+// (RFC 8866): "<type>=<value>" lines starting with v=, o= and s=, a
+// well-formed origin and at least one well-formed media description, as a
+// WebRTC peer connection has nothing to negotiate without one.
+func validateSDP(sdp string) error {
+	if sdp == "" {
+		return errMissingSDP
+	}
+
+	lines := strings.Split(strings.TrimRight(sdp, "\r\n"), "\n")
+	media := false
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if len(line) < 2 || line[1] != '=' || line[0] < 'a' || line[0] > 'z' {
+			return fmt.Errorf("invalid sdp: malformed line %d", i+1)
+		}
+
+		value := line[2:]
+		switch {
+		case i == 0 && line != "v=0":
+			return errors.New("invalid sdp: must start with v=0")
+		case i == 1 && line[0] != 'o':
+			return errors.New("invalid sdp: missing origin (o=) line")
+		case i == 2 && line[0] != 's':
+			return errors.New("invalid sdp: missing session name (s=) line")
+		}
+
+		switch line[0] {
+		case 'o':
+			if len(strings.Fields(value)) != 6 {
+				return fmt.Errorf("invalid sdp: malformed origin on line %d", i+1)
+			}
+		case 'm':
+			if err := validateMediaLine(value); err != nil {
+				return fmt.Errorf("invalid sdp: %v on line %d", err, i+1)
+			}
+			media = true
+		}
+	}
+	if len(lines) < 3 {
+		return errors.New("invalid sdp: incomplete session description")
+	}
+	if !media {
+		return errors.New("invalid sdp: no media descriptions (m=)")
+	}
+	return nil
+}
+
+// validateMediaLine checks "m=<media> <port>[/<number of ports>] <proto> <fmt> ..."
+func validateMediaLine(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return errors.New("malformed media description")
+	}
+	port, _, _ := strings.Cut(fields[1], "/")
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return errors.New("invalid media port")
+	}
+	return nil
+}
+
+// validateIceCandidate checks an ICE candidate attribute (RFC 8839 section 5.1):
+//
+//	candidate:<foundation> <component-id> <transport> <priority>
+//	    <connection-address> <port> typ <cand-type>
+//	    [raddr <address>] [rport <port>] *(<extension-att-name> <extension-att-value>)
+//
+// An empty candidate signals end-of-candidates and is accepted.
+func validateIceCandidate(candidate string) error {
+	if candidate == "" {
+		return nil
+	}
+	if len(candidate) > maxCandidateLength {
+		return fmt.Errorf("candidate exceeds %d characters", maxCandidateLength)
+	}
+
+	candidate = strings.TrimPrefix(candidate, "a=")
+	value, ok := strings.CutPrefix(candidate, "candidate:")
+	if !ok {
+		return errors.New("invalid candidate: missing candidate: prefix")
+	}
+
+	fields := strings.Fields(value)
+	if len(fields) < 8 {
+		return errors.New("invalid candidate: too few fields")
+	}
+	if !isIceChars(fields[0]) || len(fields[0]) > 32 {
+		return errors.New("invalid candidate: bad foundation")
+	}
+	if !isDigits(fields[1], 5) {
+		return errors.New("invalid candidate: bad component id")
+	}
+	if !isToken(fields[2]) {
+		return errors.New("invalid candidate: bad transport")
+	}
+	if !isDigits(fields[3], 10) {
+		return errors.New("invalid candidate: bad priority")
+	}
+	if !isConnectionAddress(fields[4]) {
+		return errors.New("invalid candidate: bad connection address")
+	}
+	if !isPort(fields[5]) {
+		return errors.New("invalid candidate: bad port")
+	}
+	if fields[6] != "typ" || !isToken(fields[7]) {
+		return errors.New("invalid candidate: bad candidate type")
+	}
+
+	// Remaining attributes come in name/value pairs
+	rest := fields[8:]
+	if len(rest)%2 != 0 {
+		return errors.New("invalid candidate: unpaired extension attribute")
+	}
+	for i := 0; i < len(rest); i += 2 {
+		name, val := rest[i], rest[i+1]
+		switch name {
+		case "raddr":
+			if !isConnectionAddress(val) {
+				return errors.New("invalid candidate: bad raddr")
+			}
+		case "rport":
+			if !isPort(val) {
+				return errors.New("invalid candidate: bad rport")
+			}
+		default:
+			if !isToken(name) {
+				return errors.New("invalid candidate: bad extension attribute")
+			}
+		}
+	}
+	return nil
+}
+
+// isIceChars reports whether s is non-empty and only contains ALPHA / DIGIT / "+" / "/"
+func isIceChars(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isAlnum(r) && r != '+' && r != '/' {
+			return false
+		}
+	}
+	return true
+}
+
+// isToken reports whether s is a non-empty SDP token (RFC 8866)
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isAlnum(r) && !strings.ContainsRune("!#$%&'*+-.^_`{|}~", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string, maxLen int) bool {
+	if s == "" || len(s) > maxLen {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isPort(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 16)
+	return err == nil
+}
+
+// isConnectionAddress accepts IPv4/IPv6 addresses and FQDNs (including mDNS .local names)
+func isConnectionAddress(s string) bool {
+	if net.ParseIP(s) != nil {
+		return true
+	}
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 {
+			return false
+		}
+		for _, r := range label {
+			if !isAlnum(r) && r != '-' && r != '_' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isAlnum(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+)
+
+// sdpLines joins SDP lines as browsers send them
+func sdpLines(lines ...string) string {
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func TestValidateSDP(t *testing.T) {
+	header := []string{"v=0", "o=- 4611731400430051336 2 IN IP4 127.0.0.1", "s=-", "t=0 0"}
+	dataChannel := sdpLines(append(header,
+		"a=group:BUNDLE 0",
+		"m=application 9 UDP/DTLS/SCTP webrtc-datachannel",
+		"c=IN IP4 0.0.0.0",
+		"a=mid:0",
+		"a=sctp-port:5000",
+	)...)
+
+	tests := []struct {
+		name  string
+		sdp   string
+		valid bool
+	}{
+		{"browser offer", testSDP, true},
+		{"browser offer with LF line endings", strings.ReplaceAll(testSDP, "\r\n", "\n"), true},
+		{"data channel only", dataChannel, true},
+		{"media port range", sdpLines(append(header, "m=video 49170/2 RTP/AVP 31")...), true},
+
+		{"empty", "", false},
+		{"truncated after origin", sdpLines(header[:2]...), false},
+		{"truncated origin", "v=0\r\no=- 4611731400430051336 2 IN IP4", false},
+		{"truncated media line", sdpLines(append(header, "m=audio 9 UDP/TLS/RTP/SAVPF")...), false},
+		{"truncated to a type", testSDP[:len(testSDP)/2] + "\r\na", false},
+		{"missing v=", sdpLines(append(header[1:], "m=audio 9 RTP/AVP 0")...), false},
+		{"wrong version", sdpLines(append([]string{"v=1"}, append(header[1:], "m=audio 9 RTP/AVP 0")...)...), false},
+		{"missing o=", sdpLines("v=0", "s=-", "t=0 0", "m=audio 9 RTP/AVP 0"), false},
+		{"missing s=", sdpLines("v=0", header[1], "t=0 0", "m=audio 9 RTP/AVP 0"), false},
+		{"missing m=", sdpLines(header...), false},
+		{"bad media port", sdpLines(append(header, "m=audio 70000 RTP/AVP 0")...), false},
+		{"uppercase type", sdpLines(append(header, "M=audio 9 RTP/AVP 0")...), false},
+		{"not sdp", "hello world", false},
+	}
+	for _, tt := range tests {
+		err := validateSDP(tt.sdp)
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("%s: got error %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateIceCandidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate string
+		valid     bool
+	}{
+		{"end of candidates", "", true},
+		{"chrome host", "candidate:842163049 1 udp 2122260223 192.168.1.2 56143 typ host generation 0 ufrag Vx3G network-id 1", true},
+		{"chrome srflx", "candidate:842163049 1 udp 1677729535 203.0.113.7 56143 typ srflx raddr 192.168.1.2 rport 56143 generation 0 ufrag Vx3G network-cost 999", true},
+		{"chrome tcp", "candidate:3 1 tcp 1518280447 192.168.1.2 9 typ host tcptype active generation 0", true},
+		{"firefox relay", "candidate:5 1 UDP 8331263 198.51.100.4 61000 typ relay raddr 203.0.113.7 rport 56143", true},
+		{"mDNS host", "candidate:1 1 UDP 2122252543 3c1f5c9e-2f6a-4c1b-9d6e-0c3d2b1f3a4e.local 54321 typ host", true},
+		{"IPv6", "candidate:2 1 udp 2122262783 2001:db8::1 50000 typ host", true},
+		{"a= prefix", "a=candidate:842163049 1 udp 2122260223 192.168.1.2 56143 typ host", true},
+		{"foundation with + and /", "candidate:Ab+/9 1 udp 2122260223 192.168.1.2 56143 typ host", true},
+
+		{"missing prefix", "842163049 1 udp 2122260223 192.168.1.2 56143 typ host", false},
+		{"too few fields", "candidate:842163049 1 udp 2122260223 192.168.1.2 56143 typ", false},
+		{"bad foundation characters", "candidate:84$2 1 udp 2122260223 192.168.1.2 56143 typ host", false},
+		{"foundation too long", "candidate:" + strings.Repeat("a", 33) + " 1 udp 2122260223 192.168.1.2 56143 typ host", false},
+		{"bad component", "candidate:1 one udp 2122260223 192.168.1.2 56143 typ host", false},
+		{"bad transport", "candidate:1 1 u(dp 2122260223 192.168.1.2 56143 typ host", false},
+		{"bad priority", "candidate:1 1 udp -5 192.168.1.2 56143 typ host", false},
+		{"bad address", "candidate:1 1 udp 2122260223 192.168..2 56143 typ host", false},
+		{"port out of range", "candidate:1 1 udp 2122260223 192.168.1.2 65536 typ host", false},
+		{"negative port", "candidate:1 1 udp 2122260223 192.168.1.2 -1 typ host", false},
+		{"non-numeric port", "candidate:1 1 udp 2122260223 192.168.1.2 http typ host", false},
+		{"missing typ", "candidate:1 1 udp 2122260223 192.168.1.2 56143 type host", false},
+		{"bad rport", "candidate:1 1 udp 1677729535 203.0.113.7 56143 typ srflx raddr 192.168.1.2 rport 99999", false},
+		{"bad raddr", "candidate:1 1 udp 1677729535 203.0.113.7 56143 typ srflx raddr 10.0.0.1/24 rport 1", false},
+		{"unpaired attribute", "candidate:1 1 udp 2122260223 192.168.1.2 56143 typ host generation", false},
+		{"oversized", "candidate:1 1 udp 2122260223 192.168.1.2 56143 typ host" + strings.Repeat(" ufrag Vx3G", 100), false},
+	}
+	for _, tt := range tests {
+		err := validateIceCandidate(tt.candidate)
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("%s: got error %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestValidatePayloadSizes(t *testing.T) {
+	mid := "0"
+	longMid := strings.Repeat("m", maxSdpMidLength+1)
+	candidate := "candidate:1 1 udp 2122260223 192.168.1.2 56143 typ host"
+
+	tests := []struct {
+		name  string
+		err   error
+		valid bool
+	}{
+		{"offer", validateSDPPayload(testSDP, testClientID), true},
+		{"offer without client_id", validateSDPPayload(testSDP, ""), false},
+		{"oversized client_id", validateSDPPayload(testSDP, strings.Repeat("c", maxClientIDLength+1)), false},
+		{"candidate", validateIceCandidatePayload(&IceCandidatePayload{Candidate: candidate, SdpMid: &mid, ClientID: testClientID}), true},
+		{"oversized sdpMid", validateIceCandidatePayload(&IceCandidatePayload{Candidate: candidate, SdpMid: &longMid, ClientID: testClientID}), false},
+		{"oversized room_id", validateJoinRoom(&JoinRoomPayload{RoomID: strings.Repeat("r", maxRoomIDLength+1)}), false},
+	}
+	for _, tt := range tests {
+		if valid := tt.err == nil; valid != tt.valid {
+			t.Errorf("%s: got error %v, want valid %v", tt.name, tt.err, tt.valid)
+		}
+	}
+}
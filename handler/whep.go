@@ -0,0 +1,15 @@
+package handler
+
+import "net/http"
+
+// whep is WebRTC-HTTP Egress Protocol: a viewer such as a plain HTTP player
+// posts its offer and the stream is sent by a publishing member of the room
+var whep = sessionKind{
+	name:      "whep",
+	peerParam: "publisher",
+}
+
+// HandleWHEP handles WHEP egress requests on /whep/{room}
+func (h *Handler) HandleWHEP(w http.ResponseWriter, r *http.Request) {
+	h.serveSession(whep, w, r)
+}
@@ -0,0 +1,16 @@
+package handler
+
+import "net/http"
+
+// whip is WebRTC-HTTP Ingestion Protocol (RFC 9725): a broadcaster such as
+// OBS or GStreamer posts its offer and the stream is received by a member
+// of the room
+var whip = sessionKind{
+	name:      "whip",
+	peerParam: "receiver",
+}
+
+// HandleWHIP handles WHIP ingest requests on /whip/{room}
+func (h *Handler) HandleWHIP(w http.ResponseWriter, r *http.Request) {
+	h.serveSession(whip, w, r)
+}
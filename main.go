@@ -26,6 +26,17 @@ func run() error {
 	// Initialize environment variables and Redis
 	config.InitEnv()
 	config.InitRedis()
+	config.InitRateLimit()
+	config.InitSignaling()
+	config.InitICE()
+	config.InitTURN()
+	config.InitGRPC()
+	config.InitAppMessages()
+	config.InitChat()
+	config.InitHost()
+	config.InitAdmin()
+	config.InitRooms()
+	config.InitDatabase()
 
 	// Prioritize Fly.io PORT environment variable
 	port := *portFlag
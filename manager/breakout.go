@@ -0,0 +1,168 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	"gosignaling/config"
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+var (
+	// ErrNestedBreakout is returned when the host of a breakout room splits it again
+	ErrNestedBreakout = errors.New("breakout rooms cannot be split")
+	// ErrBreakoutRoom is returned when a client joins a breakout room directly
+	ErrBreakoutRoom = errors.New("breakout rooms are entered through their parent room")
+)
+
+// Breakout moves members of the host's room into breakout rooms, given as
+// client IDs by breakout room name. A breakout room's ID is the room's ID
+// followed by ":" and its name, which keeps it usable in URL paths. Members on other pods are moved by their pod.
+// The host stays in the room.
+func (rm *RoomManager) Breakout(host *model.Client, assignments map[string][]string) error {
+	room, err := rm.roomRepo.GetByClientID(host.ID)
+	if err != nil || room.HostID != host.ID {
+		return ErrNotHost
+	}
+	if room.ParentID != "" {
+		return ErrNestedBreakout
+	}
+
+	var firstErr error
+	for name, clientIDs := range assignments {
+		breakoutID := BreakoutRoomID(room.ID, name)
+		for _, clientID := range clientIDs {
+			err := ErrSelfTarget
+			if clientID != host.ID {
+				err = rm.moveOrPublish(clientID, room.ID, breakoutID, room.ID)
+			}
+			if err != nil {
+				log.Printf("Failed to move %s to breakout room %s: %v", clientID, breakoutID, err)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	log.Printf("🚪 Client %s split room %s into %d breakout rooms", host.ID, room.ID, len(assignments))
+	return firstErr
+}
+
+// BreakoutRoomID returns the ID of a breakout room of parentID
+func BreakoutRoomID(parentID, name string) string {
+	return parentID + ":" + name
+}
+
+// Recall moves the members of all breakout rooms of the host's room back
+func (rm *RoomManager) Recall(host *model.Client) error {
+	room, err := rm.roomRepo.GetByClientID(host.ID)
+	if err != nil || room.HostID != host.ID {
+		return ErrNotHost
+	}
+	if room.ParentID != "" {
+		return ErrNestedBreakout
+	}
+
+	rm.RecallBreakouts(room.ID)
+	log.Printf("🚪 Client %s recalled the breakout rooms of %s", host.ID, room.ID)
+
+	if config.Rdb == nil {
+		return nil
+	}
+	payload, _ := json.Marshal(map[string]string{"room_id": room.ID})
+	return rm.publishToRedis(&model.RedisMessage{
+		Type:           model.RedisMessageTypeMove,
+		SenderClientID: host.ID,
+		RoomID:         room.ID,
+		Payload:        payload,
+	})
+}
+
+// RecallBreakouts moves the members of the breakout rooms of parentID on this
+// pod back into it (for clustering service)
+func (rm *RoomManager) RecallBreakouts(parentID string) {
+	rooms, err := rm.roomRepo.List()
+	if err != nil {
+		return
+	}
+	for _, room := range rooms {
+		if room.ParentID != parentID {
+			continue
+		}
+		for _, c := range room.Clients {
+			if err := rm.MoveClient(c.ID, room.ID, parentID, ""); err != nil {
+				log.Printf("Failed to move %s back to room %s: %v", c.ID, parentID, err)
+			}
+		}
+	}
+}
+
+// moveOrPublish moves a member of fromRoomID on this pod, or asks the other
+// pods to move it
+func (rm *RoomManager) moveOrPublish(clientID, fromRoomID, toRoomID, parentID string) error {
+	err := rm.MoveClient(clientID, fromRoomID, toRoomID, parentID)
+	if err != repository.ErrNotFound || config.Rdb == nil {
+		return err
+	}
+
+	payload, _ := json.Marshal(map[string]string{
+		"room_id":        toRoomID,
+		"parent_room_id": parentID,
+	})
+	return rm.publishToRedis(&model.RedisMessage{
+		Type:           model.RedisMessageTypeMove,
+		TargetClientID: clientID,
+		RoomID:         fromRoomID,
+		Payload:        payload,
+	})
+}
+
+// MoveClient moves a member of fromRoomID on this pod to toRoomID without
+// reconnecting it. The old room sees leave-client, the new one new-client,
+// and the client receives moved-to-room with the state of the new room.
+// parentID marks toRoomID as a breakout room (for clustering service).
+func (rm *RoomManager) MoveClient(clientID, fromRoomID, toRoomID, parentID string) error {
+	c, err := rm.roomRepo.GetClient(fromRoomID, clientID)
+	if err != nil {
+		return err
+	}
+	if c.Virtual {
+		// Virtual clients belong to the peer they were set up with
+		return repository.ErrNotFound
+	}
+
+	wasHost := false
+	if room, err := rm.roomRepo.Get(fromRoomID); err == nil {
+		wasHost = room.HostID == clientID
+	}
+
+	// Joining the new room takes the client out of the old one, so a failed
+	// move leaves it where it was
+	created, err := rm.roomRepo.AddClient(toRoomID, c, repository.JoinAdmitted)
+	if err != nil {
+		return err
+	}
+	if created {
+		log.Printf("Created new room: %s", toRoomID)
+	}
+	if parentID != "" {
+		if err := rm.roomRepo.SetParent(toRoomID, parentID); err != nil {
+			log.Printf("Failed to mark room %s as breakout room of %s: %v", toRoomID, parentID, err)
+		}
+	}
+	_, err = rm.roomRepo.Get(fromRoomID)
+	rm.left(c, fromRoomID, err == repository.ErrNotFound, wasHost)
+	log.Printf("Moved client %s from room %s to %s", clientID, fromRoomID, toRoomID)
+
+	state := rm.roomState(c, toRoomID)
+	state["from_room_id"] = fromRoomID
+	payload, _ := json.Marshal(state)
+	select {
+	case c.Send <- &model.Message{Type: model.MessageTypeMovedToRoom, Payload: payload}:
+	default:
+		log.Printf("Failed to send moved-to-room message to %s", clientID)
+	}
+	return rm.notifyNewClient(toRoomID, c)
+}
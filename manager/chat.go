@@ -0,0 +1,98 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/model"
+)
+
+// ErrNotAuthor is returned when a client edits or deletes another client's chat message
+var ErrNotAuthor = errors.New("only the author can change a chat message")
+
+// SendChat posts a chat message to the sender's room. Every member receives
+// it, including the sender, which learns the message ID that way.
+func (rm *RoomManager) SendChat(senderClient *model.Client, text string) (*model.ChatMessage, error) {
+	roomID, _, err := rm.roomRepo.FindClient(senderClient.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	chatMsg := model.NewChatMessage(senderClient, text)
+	if err := rm.chatRepo.Append(roomID, chatMsg); err != nil {
+		return nil, err
+	}
+
+	payload, _ := json.Marshal(chatMsg)
+	msg := &model.Message{
+		Type:    model.MessageTypeChat,
+		Payload: payload,
+	}
+	return chatMsg, rm.sendToRoom(roomID, senderClient.ID, "", msg)
+}
+
+// EditChat replaces the text of one of the sender's chat messages
+func (rm *RoomManager) EditChat(senderClient *model.Client, messageID, text string) (*model.ChatMessage, error) {
+	roomID, chatMsg, err := rm.authoredChat(senderClient, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	chatMsg.Text = text
+	chatMsg.EditedAt = &now
+	if err := rm.chatRepo.Update(roomID, chatMsg); err != nil {
+		return nil, err
+	}
+
+	payload, _ := json.Marshal(chatMsg)
+	msg := &model.Message{
+		Type:    model.MessageTypeChatEdit,
+		Payload: payload,
+	}
+	return chatMsg, rm.sendToRoom(roomID, senderClient.ID, "", msg)
+}
+
+// DeleteChat removes one of the sender's chat messages
+func (rm *RoomManager) DeleteChat(senderClient *model.Client, messageID string) error {
+	roomID, _, err := rm.authoredChat(senderClient, messageID)
+	if err != nil {
+		return err
+	}
+	if err := rm.chatRepo.Delete(roomID, messageID); err != nil {
+		return err
+	}
+
+	payload, _ := json.Marshal(map[string]string{
+		"message_id": messageID,
+		"client_id":  senderClient.ID,
+	})
+	msg := &model.Message{
+		Type:    model.MessageTypeChatDelete,
+		Payload: payload,
+	}
+	return rm.sendToRoom(roomID, senderClient.ID, "", msg)
+}
+
+// ChatHistory returns the latest chat messages of a room, oldest first
+func (rm *RoomManager) ChatHistory(roomID string) ([]*model.ChatMessage, error) {
+	return rm.chatRepo.Recent(roomID, config.Chat.HistorySize)
+}
+
+// authoredChat returns a message of the sender's room written by the sender
+func (rm *RoomManager) authoredChat(senderClient *model.Client, messageID string) (string, *model.ChatMessage, error) {
+	roomID, _, err := rm.roomRepo.FindClient(senderClient.ID)
+	if err != nil {
+		return "", nil, err
+	}
+	chatMsg, err := rm.chatRepo.Get(roomID, messageID)
+	if err != nil {
+		return "", nil, err
+	}
+	if chatMsg.ClientID != senderClient.ID {
+		return "", nil, ErrNotAuthor
+	}
+	return roomID, chatMsg, nil
+}
@@ -0,0 +1,228 @@
+package manager
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+// Reasons sent with room-expiring and room-closed messages
+const (
+	expiryReasonExpired     = "expired"
+	expiryReasonMaxDuration = "max-duration"
+	closeReasonAdmin        = "closed"
+)
+
+// roomDeadline returns when a room is closed and why, or the zero time if it
+// stays open
+func roomDeadline(room *model.Room) (time.Time, string) {
+	var (
+		deadline time.Time
+		reason   string
+	)
+	if room.Config != nil && !room.Config.ExpiresAt.IsZero() {
+		deadline, reason = room.Config.ExpiresAt, expiryReasonExpired
+	}
+
+	maxDuration := config.Rooms.MaxDuration
+	if room.Config != nil && room.Config.MaxDuration > 0 {
+		maxDuration = room.Config.MaxDuration
+	}
+	if maxDuration > 0 && !room.StartedAt.IsZero() {
+		if end := room.StartedAt.Add(maxDuration); deadline.IsZero() || end.Before(deadline) {
+			deadline, reason = end, expiryReasonMaxDuration
+		}
+	}
+	return deadline, reason
+}
+
+// sweepLoop warns and closes expiring rooms and disconnects idle clients
+func (rm *RoomManager) sweepLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		rm.sweep(now)
+	}
+}
+
+// sweep looks at the rooms with members on this pod, and the empty persistent
+// rooms kept here. Each pod sweeps its own rooms and publishes what it finds,
+// so a room closes on every pod when the first pod sees it expire.
+func (rm *RoomManager) sweep(now time.Time) {
+	rooms, err := rm.roomRepo.List()
+	if err != nil {
+		log.Printf("Failed to list rooms for the sweep: %v", err)
+		return
+	}
+
+	for _, room := range rooms {
+		if room.Config != nil {
+			// Pick up settings changed through another pod
+			if fresh, err := rm.roomRepo.Get(room.ID); err == nil {
+				room = fresh
+			}
+		}
+		rm.disconnectIdle(room, now)
+
+		deadline, reason := roomDeadline(room)
+		switch {
+		case deadline.IsZero():
+		case !now.Before(deadline):
+			rm.publishExpiry(room.ID, model.MessageTypeRoomClosed, deadline, reason)
+			rm.CloseRoom(room.ID, reason)
+		case !now.Before(deadline.Add(-config.Rooms.ExpiryWarning)):
+			if rm.WarnRoomExpiring(room.ID, deadline, reason) {
+				rm.publishExpiry(room.ID, model.MessageTypeRoomExpiring, deadline, reason)
+			}
+		}
+	}
+
+	// A warning that passed without the room closing, e.g. because its
+	// expiry was postponed, may be given again
+	rm.expiryMutex.Lock()
+	for roomID, deadline := range rm.expiring {
+		if now.After(deadline.Add(config.Rooms.SweepInterval)) {
+			delete(rm.expiring, roomID)
+		}
+	}
+	rm.expiryMutex.Unlock()
+}
+
+// WarnRoomExpiring tells the members of a room on this pod when it closes.
+// It reports whether they were warned, which they are once per deadline; an
+// earlier deadline from another pod wins over a later one.
+func (rm *RoomManager) WarnRoomExpiring(roomID string, expiresAt time.Time, reason string) bool {
+	rm.expiryMutex.Lock()
+	if warned, ok := rm.expiring[roomID]; ok && !warned.After(expiresAt) {
+		rm.expiryMutex.Unlock()
+		return false
+	}
+	rm.expiring[roomID] = expiresAt
+	rm.expiryMutex.Unlock()
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"room_id":      roomID,
+		"expires_at":   expiresAt.UTC(),
+		"seconds_left": int(time.Until(expiresAt).Round(time.Second).Seconds()),
+		"reason":       reason,
+	})
+	rm.DeliverToRoom(roomID, "", &model.Message{
+		Type:    model.MessageTypeRoomExpiring,
+		Payload: payload,
+	})
+	log.Printf("⏳ Room %s closes at %s (%s)", roomID, expiresAt.UTC().Format(time.RFC3339), reason)
+	return true
+}
+
+// CloseRoom disconnects the members of a room on this pod with a room-closed
+// message and denies the clients waiting in its lobby. A persistent room is
+// kept for its next meeting unless it expired for good.
+func (rm *RoomManager) CloseRoom(roomID, reason string) {
+	rm.expiryMutex.Lock()
+	delete(rm.expiring, roomID)
+	rm.expiryMutex.Unlock()
+
+	rm.closeLobby(roomID)
+
+	// Without members on this pod there is nobody to disconnect
+	clients, err := rm.roomRepo.Clients(roomID)
+	if err != nil && err != repository.ErrNotFound {
+		log.Printf("Failed to list the members of room %s: %v", roomID, err)
+		return
+	}
+
+	payload, _ := json.Marshal(map[string]string{
+		"room_id": roomID,
+		"reason":  reason,
+	})
+	msg := &model.Message{
+		Type:    model.MessageTypeRoomClosed,
+		Payload: payload,
+	}
+	// Everyone is told before anyone leaves, so nobody reacts to the others leaving
+	for _, c := range clients {
+		select {
+		case c.Send <- msg:
+		default:
+			log.Printf("Failed to send room-closed message to %s", c.ID)
+		}
+	}
+	for _, c := range clients {
+		rm.LeaveRoom(c)
+	}
+
+	if reason == expiryReasonExpired {
+		if err := rm.roomRepo.SetConfig(roomID, nil); err != nil && err != repository.ErrNotFound {
+			log.Printf("Failed to remove configuration of room %s: %v", roomID, err)
+		}
+	}
+	if len(clients) > 0 {
+		log.Printf("🔒 Closed room %s (%s)", roomID, reason)
+	}
+}
+
+// EndRoom closes a room on every pod, as asked by an administrator. Without
+// Redis it fails with repository.ErrNotFound for a room without members.
+func (rm *RoomManager) EndRoom(roomID, reason string) error {
+	if reason == "" || reason == expiryReasonExpired {
+		// "expired" would also remove the configuration of a persistent room
+		reason = closeReasonAdmin
+	}
+	if config.Rdb == nil {
+		if _, err := rm.roomRepo.Clients(roomID); err != nil {
+			return err
+		}
+	}
+	rm.publishExpiry(roomID, model.MessageTypeRoomClosed, time.Now(), reason)
+	rm.CloseRoom(roomID, reason)
+	return nil
+}
+
+// disconnectIdle disconnects the members of a room that sent nothing for
+// the idle timeout. Virtual clients are left to their HTTP sessions.
+func (rm *RoomManager) disconnectIdle(room *model.Room, now time.Time) {
+	if config.Rooms.IdleTimeout <= 0 {
+		return
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"timeout_seconds": int(config.Rooms.IdleTimeout.Seconds()),
+	})
+	for _, c := range room.Clients {
+		if c.Virtual || now.Sub(c.LastActive()) < config.Rooms.IdleTimeout {
+			continue
+		}
+		select {
+		case c.Send <- &model.Message{Type: model.MessageTypeIdleTimeout, Payload: payload}:
+		default:
+			log.Printf("Failed to send idle-timeout message to %s", c.ID)
+		}
+		log.Printf("💤 Disconnecting idle client %s from room %s", c.ID, room.ID)
+		rm.LeaveRoom(c)
+	}
+}
+
+// publishExpiry tells the other pods to warn or close a room
+func (rm *RoomManager) publishExpiry(roomID string, msgType model.MessageType, expiresAt time.Time, reason string) {
+	if config.Rdb == nil {
+		return
+	}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"expires_at": expiresAt.UTC(),
+		"reason":     reason,
+	})
+	err := rm.publishToRedis(&model.RedisMessage{
+		Type:        model.RedisMessageTypeExpire,
+		MessageType: msgType,
+		RoomID:      roomID,
+		Payload:     payload,
+	})
+	if err != nil {
+		log.Printf("Failed to publish %s for room %s: %v", msgType, roomID, err)
+	}
+}
@@ -0,0 +1,142 @@
+package manager
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/model"
+	"gosignaling/repository/mem"
+)
+
+func TestMain(m *testing.M) {
+	// Every join, leave and closed room is logged
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// setRooms changes config.Rooms for the rest of a test
+func setRooms(t *testing.T, change func(*config.RoomsConfig)) {
+	saved := config.Rooms
+	t.Cleanup(func() { config.Rooms = saved })
+	change(&config.Rooms)
+}
+
+// joinTest creates a client named name and joins roomID with it
+func joinTest(t *testing.T, rm *RoomManager, roomID, name string) *model.Client {
+	t.Helper()
+	c := model.NewClient(name)
+	if err := rm.JoinRoom(c, roomID, "", false); err != nil {
+		t.Fatalf("join %s: %v", name, err)
+	}
+	return c
+}
+
+// queued returns the first queued message of c of type msgType, dropping the
+// ones before it, or nil when there is none
+func queued(c *model.Client, msgType model.MessageType) *model.Message {
+	for {
+		select {
+		case msg := <-c.Send:
+			if msg.Type == msgType {
+				return msg
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// TestSweepIdleClients checks that the sweeper disconnects a member that sent
+// nothing for the idle timeout, and only that member
+func TestSweepIdleClients(t *testing.T) {
+	setRooms(t, func(r *config.RoomsConfig) { r.IdleTimeout = time.Minute })
+	rm := NewRoomManager(mem.NewRoomRepository(), mem.NewChatRepository(10), nil)
+	alice := joinTest(t, rm, "room", "Alice")
+	bob := joinTest(t, rm, "room", "Bob")
+	time.Sleep(2 * time.Millisecond)
+	bob.Touch()
+
+	rm.sweep(alice.LastActive().Add(30 * time.Second))
+	if !rm.IsMember(alice.ID) {
+		t.Fatal("alice was disconnected before the idle timeout")
+	}
+
+	rm.sweep(alice.LastActive().Add(time.Minute))
+	msg := queued(alice, model.MessageTypeIdleTimeout)
+	if msg == nil {
+		t.Fatal("alice got no idle-timeout")
+	}
+	var payload struct {
+		TimeoutSeconds int `json:"timeout_seconds"`
+	}
+	json.Unmarshal(msg.Payload, &payload)
+	if payload.TimeoutSeconds != 60 {
+		t.Errorf("timeout_seconds: got %d, want 60", payload.TimeoutSeconds)
+	}
+	if rm.IsMember(alice.ID) {
+		t.Error("idle alice is still a member")
+	}
+	if queued(bob, model.MessageTypeLeaveClient) == nil {
+		t.Error("bob was not told that alice left")
+	}
+	if !rm.IsMember(bob.ID) || queued(bob, model.MessageTypeIdleTimeout) != nil {
+		t.Error("active bob was disconnected")
+	}
+}
+
+// TestSweepMaxDuration checks that the members of a room are warned once
+// before its maximum duration and disconnected when it is reached
+func TestSweepMaxDuration(t *testing.T) {
+	setRooms(t, func(r *config.RoomsConfig) {
+		r.MaxDuration = time.Hour
+		r.ExpiryWarning = time.Minute
+	})
+	rm := NewRoomManager(mem.NewRoomRepository(), mem.NewChatRepository(10), nil)
+	alice := joinTest(t, rm, "room", "Alice")
+	bob := joinTest(t, rm, "room", "Bob")
+	room, err := rm.GetRoomByClientID(alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := room.StartedAt
+
+	rm.sweep(start.Add(30 * time.Minute))
+	if queued(alice, model.MessageTypeRoomExpiring) != nil {
+		t.Fatal("warned before the expiry warning period")
+	}
+
+	rm.sweep(start.Add(59*time.Minute + 30*time.Second))
+	for _, c := range []*model.Client{alice, bob} {
+		msg := queued(c, model.MessageTypeRoomExpiring)
+		if msg == nil {
+			t.Fatalf("%s got no room-expiring", c.Profile().Name)
+		}
+		var payload struct {
+			ExpiresAt time.Time `json:"expires_at"`
+			Reason    string    `json:"reason"`
+		}
+		json.Unmarshal(msg.Payload, &payload)
+		if !payload.ExpiresAt.Equal(start.Add(time.Hour)) || payload.Reason != expiryReasonMaxDuration {
+			t.Errorf("room-expiring: got %+v, want max-duration at %v", payload, start.Add(time.Hour))
+		}
+	}
+	rm.sweep(start.Add(59*time.Minute + 45*time.Second))
+	if queued(alice, model.MessageTypeRoomExpiring) != nil {
+		t.Error("warned twice for the same deadline")
+	}
+
+	rm.sweep(start.Add(time.Hour))
+	for _, c := range []*model.Client{alice, bob} {
+		if queued(c, model.MessageTypeRoomClosed) == nil {
+			t.Errorf("%s got no room-closed", c.Profile().Name)
+		}
+		if rm.IsMember(c.ID) {
+			t.Errorf("%s is still a member of the closed room", c.Profile().Name)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package manager
+
+import (
+	"errors"
+
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+// ErrNoSessionLog is returned by the history queries when no database is
+// configured
+var ErrNoSessionLog = errors.New("session log is not configured")
+
+// RoomHistory returns the records of rooms opening and closing, newest first
+func (rm *RoomManager) RoomHistory(f repository.HistoryFilter) ([]*model.RoomRecord, error) {
+	if rm.sessionLog == nil {
+		return nil, ErrNoSessionLog
+	}
+	return rm.sessionLog.Rooms(f)
+}
+
+// SessionHistory returns the records of clients joining and leaving rooms,
+// newest first
+func (rm *RoomManager) SessionHistory(f repository.HistoryFilter) ([]*model.SessionRecord, error) {
+	if rm.sessionLog == nil {
+		return nil, ErrNoSessionLog
+	}
+	return rm.sessionLog.Sessions(f)
+}
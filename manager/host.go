@@ -0,0 +1,201 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	"gosignaling/config"
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+var (
+	// ErrNotHost is returned when a client that is not host moderates its room
+	ErrNotHost = errors.New("only the host can do this")
+	// ErrSelfTarget is returned when the host targets itself
+	ErrSelfTarget = errors.New("cannot target yourself")
+)
+
+// Kick removes a member from the host's room. The member receives a kicked
+// message, and its transport closes the connection.
+func (rm *RoomManager) Kick(host *model.Client, targetClientID, reason string) error {
+	roomID, err := rm.requireHost(host, targetClientID)
+	if err != nil {
+		return err
+	}
+	return rm.kick(roomID, host.ID, targetClientID, reason)
+}
+
+// RemoveMember kicks a member of a room on behalf of an administrator; the
+// kicked message carries no client_id
+func (rm *RoomManager) RemoveMember(roomID, clientID, reason string) error {
+	return rm.kick(roomID, "", clientID, reason)
+}
+
+// kick sends a kicked message to a member of a room and removes it
+func (rm *RoomManager) kick(roomID, byClientID, targetClientID, reason string) error {
+	payload, _ := json.Marshal(map[string]string{
+		"client_id": byClientID,
+		"reason":    reason,
+	})
+	msg := &model.Message{
+		Type:    model.MessageTypeKicked,
+		Payload: payload,
+	}
+
+	target, err := rm.roomRepo.GetClient(roomID, targetClientID)
+	if err != nil {
+		// Target on another pod, which disconnects it the same way
+		return rm.sendToClient(roomID, byClientID, targetClientID, msg)
+	}
+	log.Printf("👢 Client %q kicked %s from room %s", byClientID, target.ID, roomID)
+	return rm.Disconnect(target, msg)
+}
+
+// Disconnect closes the connection of a client on this pod after delivering
+// msg, and takes the client out of its room right away so that it cannot act
+// before its transport has closed the connection
+func (rm *RoomManager) Disconnect(c *model.Client, msg *model.Message) error {
+	c.Disconnect(msg)
+	return rm.LeaveRoom(c)
+}
+
+// RequestMute asks a member of the host's room to mute its audio or video.
+// Muting stays up to the member's client.
+func (rm *RoomManager) RequestMute(host *model.Client, targetClientID string, audio, video bool) error {
+	roomID, err := rm.requireHost(host, targetClientID)
+	if err != nil {
+		return err
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"client_id": host.ID,
+		"audio":     audio,
+		"video":     video,
+	})
+	return rm.sendToClient(roomID, host.ID, targetClientID, &model.Message{
+		Type:    model.MessageTypeMuteRequest,
+		Payload: payload,
+	})
+}
+
+// LockRoom locks or unlocks the host's room on every pod. New clients wait in
+// the lobby of a locked room unless they claim the host role; unlocking admits
+// them.
+func (rm *RoomManager) LockRoom(host *model.Client, locked bool) error {
+	roomID, err := rm.requireHost(host, "")
+	if err != nil {
+		return err
+	}
+	if err := rm.roomRepo.SetLocked(roomID, locked); err != nil {
+		return err
+	}
+	log.Printf("🔒 Client %s set room %s locked=%t", host.ID, roomID, locked)
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"client_id": host.ID,
+		"locked":    locked,
+	})
+	if err := rm.sendToRoom(roomID, host.ID, "", &model.Message{
+		Type:    model.MessageTypeRoomLocked,
+		Payload: payload,
+	}); err != nil {
+		return err
+	}
+
+	// Nobody needs to wait for an unlocked room, on any pod
+	if !locked {
+		rm.admitAll(roomID)
+		if err := rm.publishLobby(roomID, "", lobbyAdmitAll, ""); err != nil {
+			log.Printf("Failed to publish unlock of room %s: %v", roomID, err)
+		}
+	}
+	return nil
+}
+
+// TransferHost hands the host role to another member of the room, on any pod
+func (rm *RoomManager) TransferHost(host *model.Client, targetClientID string) error {
+	roomID, err := rm.requireHost(host, targetClientID)
+	if err != nil {
+		return err
+	}
+	if target, err := rm.roomRepo.GetClient(roomID, targetClientID); err == nil && target.Virtual {
+		return repository.ErrNotFound
+	}
+	return rm.setHost(roomID, targetClientID)
+}
+
+// requireHost returns the room of a client that is its host. A non-empty
+// targetClientID must be another client.
+func (rm *RoomManager) requireHost(c *model.Client, targetClientID string) (string, error) {
+	room, err := rm.roomRepo.GetByClientID(c.ID)
+	if err == repository.ErrNotFound {
+		// Outside a room there is nothing to moderate
+		return "", ErrNotHost
+	}
+	if err != nil {
+		return "", err
+	}
+	if room.HostID != c.ID {
+		return "", ErrNotHost
+	}
+	if targetClientID == c.ID {
+		return "", ErrSelfTarget
+	}
+	return room.ID, nil
+}
+
+// setHost makes a member host and tells the room
+func (rm *RoomManager) setHost(roomID, clientID string) error {
+	if err := rm.roomRepo.SetHost(roomID, clientID); err != nil {
+		return err
+	}
+	log.Printf("👑 Client %s is now host of room %s", clientID, roomID)
+	rm.notifyHostChanged(roomID, clientID)
+	return nil
+}
+
+// notifyHostChanged tells every member of a room, including those on other
+// pods, who its host is
+func (rm *RoomManager) notifyHostChanged(roomID, hostID string) {
+	payload, _ := json.Marshal(map[string]string{"client_id": hostID})
+	msg := &model.Message{
+		Type:    model.MessageTypeHostChanged,
+		Payload: payload,
+	}
+	if err := rm.sendToRoom(roomID, hostID, "", msg); err != nil {
+		log.Printf("Failed to publish host change of room %s: %v", roomID, err)
+	}
+	// Clients waiting on other pods are presented by their pods
+	rm.notifyLobby(roomID)
+	if err := rm.publishLobby(roomID, "", lobbyNotify, ""); err != nil {
+		log.Printf("Failed to publish lobby of room %s: %v", roomID, err)
+	}
+}
+
+// sendToClient delivers a message to a member of a room, on this pod or
+// through Redis on another pod
+func (rm *RoomManager) sendToClient(roomID, senderClientID, targetClientID string, msg *model.Message) error {
+	target, err := rm.roomRepo.GetClient(roomID, targetClientID)
+	if err != nil {
+		if config.Rdb == nil {
+			return repository.ErrNotFound
+		}
+		return rm.publishToRedis(&model.RedisMessage{
+			Type:           model.RedisMessageTypeDirect,
+			MessageType:    msg.Type,
+			SenderClientID: senderClientID,
+			TargetClientID: targetClientID,
+			RoomID:         roomID,
+			Payload:        msg.Payload,
+		})
+	}
+
+	select {
+	case target.Send <- msg:
+	default:
+		log.Printf("Failed to send %s message to %s", msg.Type, targetClientID)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+// ErrWaitingForAdmission is returned by JoinRoom when the client was placed
+// in the lobby of a locked room
+var ErrWaitingForAdmission = errors.New("waiting for admission")
+
+// Reasons sent with denied messages when the host did not give one
+const (
+	denyReasonTimeout    = "timed out"
+	denyReasonRoomClosed = "room closed"
+)
+
+// Lobby actions published for the pods the waiting clients are connected to
+const (
+	lobbyAdmit    = "admit"
+	lobbyDeny     = "deny"
+	lobbyAdmitAll = "admit-all"
+	lobbyClose    = "close"
+	lobbyNotify   = "notify"
+)
+
+// lobbyEntry is a client waiting for admission to a locked room
+type lobbyEntry struct {
+	client *model.Client
+	roomID string
+	timer  *time.Timer
+}
+
+// enterLobby places a client in the lobby of a locked room and asks the host
+// to admit it. The lobby entry is kept on this pod; the host's answers reach
+// it through Redis when the host is on another pod.
+func (rm *RoomManager) enterLobby(c *model.Client, roomID string) error {
+	entry := &lobbyEntry{client: c, roomID: roomID}
+	rm.lobbyMutex.Lock()
+	rm.lobby[c.ID] = entry
+	entry.timer = time.AfterFunc(config.Host.LobbyTimeout, func() {
+		if rm.removeFromLobby(entry) {
+			log.Printf("⏳ Admission of %s to room %s timed out", c.ID, roomID)
+			rm.deny(entry, denyReasonTimeout)
+			rm.notifyHost(roomID, admissionMessage(model.MessageTypeAdmissionCancelled, c.Participant()))
+		}
+	})
+	rm.lobbyMutex.Unlock()
+
+	log.Printf("⏳ Client %s is waiting for admission to room %s", c.ID, roomID)
+	rm.notifyHost(roomID, admissionMessage(model.MessageTypeAdmissionRequest, c.Participant()))
+	return ErrWaitingForAdmission
+}
+
+// Admit moves a client from the lobby into the host's room, on this pod or
+// through Redis on the pod the client waits on
+func (rm *RoomManager) Admit(host *model.Client, clientID string) error {
+	roomID, err := rm.requireHost(host, clientID)
+	if err != nil {
+		return err
+	}
+	entry, err := rm.takeFromLobby(roomID, clientID)
+	if err == repository.ErrNotFound && config.Rdb != nil {
+		log.Printf("✅ Client %s admitted %s to room %s on another pod", host.ID, clientID, roomID)
+		return rm.publishLobby(roomID, clientID, lobbyAdmit, "")
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("✅ Client %s admitted %s to room %s", host.ID, clientID, roomID)
+	return rm.admit(entry)
+}
+
+// admitAll moves every client waiting for a room into it, e.g. once the room
+// is unlocked
+func (rm *RoomManager) admitAll(roomID string) {
+	for _, entry := range rm.lobbyEntries(roomID) {
+		if !rm.removeFromLobby(entry) {
+			continue
+		}
+		if err := rm.admit(entry); err != nil {
+			log.Printf("Failed to admit %s to room %s: %v", entry.client.ID, roomID, err)
+		}
+	}
+}
+
+// admit adds a client taken out of the lobby to its room with the normal
+// joined and new-client messages
+func (rm *RoomManager) admit(entry *lobbyEntry) error {
+	c := entry.client
+	if _, err := rm.roomRepo.AddClient(entry.roomID, c, repository.JoinAdmitted); err != nil {
+		return err
+	}
+
+	select {
+	case c.Send <- rm.JoinedMessage(c, entry.roomID):
+	default:
+		log.Printf("Failed to send joined message to %s", c.ID)
+	}
+	return rm.notifyNewClient(entry.roomID, c)
+}
+
+// Deny rejects a client waiting in the lobby of the host's room, on this pod
+// or through Redis on the pod the client waits on
+func (rm *RoomManager) Deny(host *model.Client, clientID, reason string) error {
+	roomID, err := rm.requireHost(host, clientID)
+	if err != nil {
+		return err
+	}
+	entry, err := rm.takeFromLobby(roomID, clientID)
+	if err == repository.ErrNotFound && config.Rdb != nil {
+		log.Printf("🚫 Client %s denied %s admission to room %s on another pod", host.ID, clientID, roomID)
+		return rm.publishLobby(roomID, clientID, lobbyDeny, reason)
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("🚫 Client %s denied %s admission to room %s", host.ID, clientID, roomID)
+	rm.deny(entry, reason)
+	return nil
+}
+
+// HandleLobby applies a lobby action published by another pod to the clients
+// waiting on this pod (for clustering service)
+func (rm *RoomManager) HandleLobby(roomID, clientID, action, reason string) {
+	switch action {
+	case lobbyAdmit, lobbyDeny:
+		entry, err := rm.takeFromLobby(roomID, clientID)
+		if err != nil {
+			return
+		}
+		if action == lobbyDeny {
+			rm.deny(entry, reason)
+		} else if err := rm.admit(entry); err != nil {
+			log.Printf("Failed to admit %s to room %s: %v", clientID, roomID, err)
+		}
+	case lobbyAdmitAll:
+		rm.admitAll(roomID)
+	case lobbyClose:
+		rm.closeLobby(roomID)
+	case lobbyNotify:
+		rm.notifyLobby(roomID)
+	default:
+		log.Printf("⚠️ Unknown lobby action: %s", action)
+	}
+}
+
+// publishLobby asks the other pods to apply a lobby action to a room's
+// lobby, or to one client in it
+func (rm *RoomManager) publishLobby(roomID, clientID, action, reason string) error {
+	if config.Rdb == nil {
+		return nil
+	}
+	payload, _ := json.Marshal(map[string]string{
+		"action": action,
+		"reason": reason,
+	})
+	return rm.publishToRedis(&model.RedisMessage{
+		Type:           model.RedisMessageTypeLobby,
+		TargetClientID: clientID,
+		RoomID:         roomID,
+		Payload:        payload,
+	})
+}
+
+// takeFromLobby removes a client waiting for a room from the lobby
+func (rm *RoomManager) takeFromLobby(roomID, clientID string) (*lobbyEntry, error) {
+	rm.lobbyMutex.Lock()
+	defer rm.lobbyMutex.Unlock()
+	entry, ok := rm.lobby[clientID]
+	if !ok || entry.roomID != roomID {
+		return nil, repository.ErrNotFound
+	}
+	delete(rm.lobby, clientID)
+	entry.timer.Stop()
+	return entry, nil
+}
+
+// removeFromLobby removes an entry unless it was already taken out
+func (rm *RoomManager) removeFromLobby(entry *lobbyEntry) bool {
+	rm.lobbyMutex.Lock()
+	defer rm.lobbyMutex.Unlock()
+	if rm.lobby[entry.client.ID] != entry {
+		return false
+	}
+	delete(rm.lobby, entry.client.ID)
+	entry.timer.Stop()
+	return true
+}
+
+// leaveLobby takes a client out of the lobby it waits in and tells the host.
+// It reports whether the client was waiting.
+func (rm *RoomManager) leaveLobby(c *model.Client) bool {
+	rm.lobbyMutex.Lock()
+	entry, ok := rm.lobby[c.ID]
+	rm.lobbyMutex.Unlock()
+	if !ok || !rm.removeFromLobby(entry) {
+		return false
+	}
+
+	log.Printf("Client %s stopped waiting for room %s", c.ID, entry.roomID)
+	rm.notifyHost(entry.roomID, admissionMessage(model.MessageTypeAdmissionCancelled, c.Participant()))
+	return true
+}
+
+// closeLobby denies every client waiting for a deleted room
+func (rm *RoomManager) closeLobby(roomID string) {
+	for _, entry := range rm.lobbyEntries(roomID) {
+		if rm.removeFromLobby(entry) {
+			rm.deny(entry, denyReasonRoomClosed)
+		}
+	}
+}
+
+// notifyLobby sends the pending admission requests of a room to its new host
+func (rm *RoomManager) notifyLobby(roomID string) {
+	for _, entry := range rm.lobbyEntries(roomID) {
+		rm.notifyHost(roomID, admissionMessage(model.MessageTypeAdmissionRequest, entry.client.Participant()))
+	}
+}
+
+func (rm *RoomManager) lobbyEntries(roomID string) []*lobbyEntry {
+	rm.lobbyMutex.Lock()
+	defer rm.lobbyMutex.Unlock()
+	var entries []*lobbyEntry
+	for _, entry := range rm.lobby {
+		if entry.roomID == roomID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// deny tells a client that was taken out of the lobby that it was not admitted
+func (rm *RoomManager) deny(entry *lobbyEntry, reason string) {
+	payload, _ := json.Marshal(map[string]string{
+		"room_id": entry.roomID,
+		"reason":  reason,
+	})
+	select {
+	case entry.client.Send <- &model.Message{Type: model.MessageTypeDenied, Payload: payload}:
+	default:
+		log.Printf("Failed to send denied message to %s", entry.client.ID)
+	}
+}
+
+// notifyHost sends a message to the host of a room
+func (rm *RoomManager) notifyHost(roomID string, msg *model.Message) {
+	room, err := rm.roomRepo.Get(roomID)
+	if err != nil || room.HostID == "" {
+		return
+	}
+	if err := rm.sendToClient(roomID, "", room.HostID, msg); err != nil {
+		log.Printf("Failed to send %s message to host of room %s: %v", msg.Type, roomID, err)
+	}
+}
+
+func admissionMessage(msgType model.MessageType, p model.Participant) *model.Message {
+	payload, _ := json.Marshal(p)
+	return &model.Message{
+		Type:    msgType,
+		Payload: payload,
+	}
+}
@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"encoding/json"
+	"log"
+
+	"gosignaling/config"
+	"gosignaling/model"
+)
+
+// Broadcast sends application data to the other members of the sender's room,
+// directly on this pod and through Redis for members on other pods
+func (rm *RoomManager) Broadcast(senderClient *model.Client, data json.RawMessage) error {
+	roomID, _, err := rm.roomRepo.FindClient(senderClient.ID)
+	if err != nil {
+		return err
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"client_id": senderClient.ID,
+		"data":      data,
+	})
+	msg := &model.Message{
+		Type:    model.MessageTypeBroadcast,
+		Payload: payload,
+	}
+	return rm.sendToRoom(roomID, senderClient.ID, senderClient.ID, msg)
+}
+
+// sendToRoom delivers a message to the room's members on this pod, except
+// excludeClientID, and publishes it for the members on other pods
+func (rm *RoomManager) sendToRoom(roomID, senderClientID, excludeClientID string, msg *model.Message) error {
+	rm.DeliverToRoom(roomID, excludeClientID, msg)
+
+	if config.Rdb == nil {
+		return nil
+	}
+	return rm.publishToRedis(&model.RedisMessage{
+		Type:           model.RedisMessageTypeBroadcast,
+		MessageType:    msg.Type,
+		SenderClientID: senderClientID,
+		RoomID:         roomID,
+		Payload:        msg.Payload,
+	})
+}
+
+// DeliverToRoom queues a message for the members of a room on this pod,
+// except excludeClientID (for clustering service)
+func (rm *RoomManager) DeliverToRoom(roomID, excludeClientID string, msg *model.Message) {
+	clients, err := rm.roomRepo.Clients(roomID)
+	if err != nil {
+		return
+	}
+
+	for _, client := range clients {
+		// Virtual clients only exchange SDP with their peer
+		if client.ID == excludeClientID || client.Virtual {
+			continue
+		}
+		select {
+		case client.Send <- msg:
+		default:
+			log.Printf("Failed to send %s message to %s", msg.Type, client.ID)
+		}
+	}
+}
+
+// SendDirect sends application data to one member of the sender's room
+func (rm *RoomManager) SendDirect(senderClient *model.Client, data json.RawMessage, targetClientID string) error {
+	roomID, _, err := rm.roomRepo.FindClient(senderClient.ID)
+	if err != nil {
+		return err
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"client_id": senderClient.ID,
+		"data":      data,
+	})
+
+	targetClient, err := rm.roomRepo.GetClient(roomID, targetClientID)
+	if err != nil {
+		// Target client not in local room, publish to Redis for other pods
+		log.Printf("Target client %s not found locally, publishing to Redis", targetClientID)
+		return rm.publishToRedis(&model.RedisMessage{
+			Type:           model.RedisMessageTypeDirect,
+			SenderClientID: senderClient.ID,
+			TargetClientID: targetClientID,
+			RoomID:         roomID,
+			Payload:        payload,
+		})
+	}
+
+	msg := &model.Message{
+		Type:    model.MessageTypeDirect,
+		Payload: payload,
+	}
+	select {
+	case targetClient.Send <- msg:
+		log.Printf("📤 Sent direct message locally to %s", targetClientID)
+	default:
+		log.Printf("Failed to send direct message to %s", targetClientID)
+	}
+
+	return nil
+}
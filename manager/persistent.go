@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+// ErrWrongPassword is returned by JoinRoom when a client does not send the
+// password of a persistent room
+var ErrWrongPassword = errors.New("wrong room password")
+
+// RoomSettings are the settings of a persistent room given through the admin API
+type RoomSettings struct {
+	Capacity         int
+	Password         string
+	Topology         string
+	RecordingAllowed bool
+	// Lifetime closes the room for good that long after it was created, and
+	// ExpiresAt at a fixed time; without either it is kept until removed
+	Lifetime  time.Duration
+	ExpiresAt time.Time
+	// MaxDuration closes each meeting in the room that long after it started
+	MaxDuration time.Duration
+}
+
+// ConfigureRoom makes a room persistent, or replaces the settings of a
+// persistent room. It reports whether the room became persistent.
+func (rm *RoomManager) ConfigureRoom(roomID string, settings RoomSettings) (*model.Room, bool, error) {
+	cfg := &model.RoomConfig{
+		Capacity:         settings.Capacity,
+		Topology:         settings.Topology,
+		RecordingAllowed: settings.RecordingAllowed,
+		MaxDuration:      settings.MaxDuration,
+		// Whole seconds in UTC survive the JSON round trip unchanged
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	created := true
+	if room, err := rm.roomRepo.Get(roomID); err == nil && room.Persistent() {
+		cfg.CreatedAt = room.Config.CreatedAt
+		created = false
+	}
+	if settings.Lifetime > 0 {
+		cfg.ExpiresAt = cfg.CreatedAt.Add(settings.Lifetime)
+	} else if !settings.ExpiresAt.IsZero() {
+		cfg.ExpiresAt = settings.ExpiresAt.UTC().Truncate(time.Second)
+	}
+	cfg.SetPassword(settings.Password)
+
+	if err := rm.roomRepo.SetConfig(roomID, cfg); err != nil {
+		return nil, false, err
+	}
+	if created {
+		log.Printf("🏠 Created persistent room %s", roomID)
+	} else {
+		log.Printf("🏠 Updated persistent room %s", roomID)
+	}
+	room, err := rm.roomRepo.Get(roomID)
+	return room, created, err
+}
+
+// RemoveRoomConfig makes a persistent room ad-hoc again. Its members stay,
+// and it is deleted once they have left.
+func (rm *RoomManager) RemoveRoomConfig(roomID string) error {
+	if _, err := rm.GetPersistentRoom(roomID); err != nil {
+		return err
+	}
+	if err := rm.roomRepo.SetConfig(roomID, nil); err != nil && err != repository.ErrNotFound {
+		return err
+	}
+	log.Printf("🏠 Removed persistent room %s", roomID)
+	return nil
+}
+
+// GetPersistentRoom returns a snapshot of a persistent room
+func (rm *RoomManager) GetPersistentRoom(roomID string) (*model.Room, error) {
+	room, err := rm.roomRepo.Get(roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !room.Persistent() {
+		return nil, repository.ErrNotFound
+	}
+	return room, nil
+}
+
+// GetPersistentRooms returns the persistent rooms sorted by ID
+func (rm *RoomManager) GetPersistentRooms() ([]*model.Room, error) {
+	rooms, err := rm.roomRepo.ListPersistent()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].ID < rooms[j].ID
+	})
+	return rooms, nil
+}
@@ -3,6 +3,9 @@ package manager
 import (
 	"encoding/json"
 	"log"
+	"sort"
+	"sync"
+	"time"
 
 	"gosignaling/config"
 	"gosignaling/model"
@@ -11,137 +14,304 @@ import (
 
 // RoomManager manages room operations
 type RoomManager struct {
-	roomRepo repository.Room
+	roomRepo   repository.Room
+	chatRepo   repository.Chat
+	sessionLog repository.SessionLog // nil without a database
+
+	lobbyMutex sync.Mutex
+	lobby      map[string]*lobbyEntry // waiting clients by client ID
+
+	expiryMutex sync.Mutex
+	expiring    map[string]time.Time // warned rooms by room ID, with the closing time
 }
 
-// NewRoomManager creates a new room manager
-func NewRoomManager(roomRepo repository.Room) *RoomManager {
-	return &RoomManager{
-		roomRepo: roomRepo,
+// NewRoomManager creates a new room manager and starts its sweeper, which
+// closes expired rooms and disconnects idle clients. sessionLog may be nil
+// when no database is configured.
+func NewRoomManager(roomRepo repository.Room, chatRepo repository.Chat, sessionLog repository.SessionLog) *RoomManager {
+	rm := &RoomManager{
+		roomRepo:   roomRepo,
+		chatRepo:   chatRepo,
+		sessionLog: sessionLog,
+		lobby:      make(map[string]*lobbyEntry),
+		expiring:   make(map[string]time.Time),
 	}
+	go rm.sweepLoop(config.Rooms.SweepInterval)
+	return rm
 }
 
-// JoinRoom handles a client joining a room
-func (rm *RoomManager) JoinRoom(c *model.Client, roomID string) error {
-	room, err := rm.roomRepo.Get(roomID)
-	if err == repository.ErrNotFound {
-		// Create room if it doesn't exist
-		room = model.NewRoom(roomID)
-		if _, err := rm.roomRepo.Create(room); err != nil {
+// JoinRoom handles a client joining a room. asHost makes the client the
+// room's host, e.g. after it presented a host token. Other clients must send
+// the password of a persistent room that has one, and wait in the lobby of a
+// locked room with ErrWaitingForAdmission.
+func (rm *RoomManager) JoinRoom(c *model.Client, roomID, password string, asHost bool) error {
+	// A new join replaces a pending one
+	rm.leaveLobby(c)
+
+	// Leave the current room first so its members are notified
+	if currentRoomID, _, err := rm.roomRepo.FindClient(c.ID); err == nil {
+		if currentRoomID == roomID {
+			if asHost {
+				return rm.setHost(roomID, c.ID)
+			}
+			return nil
+		}
+		if err := rm.LeaveRoom(c); err != nil {
 			return err
 		}
-		log.Printf("Created new room: %s", roomID)
-	} else if err != nil {
-		return err
 	}
 
-	// Add client to room
-	room.Clients[c.ID] = c
-	if _, err := rm.roomRepo.Update(room); err != nil {
+	// Breakout rooms are only entered through their parent's host
+	if room, err := rm.roomRepo.Get(roomID); err == nil && room.ParentID != "" {
+		return ErrBreakoutRoom
+	}
+
+	mode := repository.JoinAsMember
+	if asHost {
+		mode = repository.JoinAsHost
+	} else if room, err := rm.roomRepo.Get(roomID); err == nil && room.Persistent() && !room.Config.CheckPassword(password) {
+		return ErrWrongPassword
+	}
+
+	// Add client to room, creating the room if it doesn't exist
+	created, err := rm.roomRepo.AddClient(roomID, c, mode)
+	if err == repository.ErrRoomLocked && !c.Virtual {
+		return rm.enterLobby(c, roomID)
+	}
+	if err != nil {
 		return err
 	}
+	if created {
+		log.Printf("Created new room: %s", roomID)
+	}
 
 	log.Printf("Client %s joined room %s", c.ID, roomID)
 
-	// Notify other clients in the room
-	return rm.notifyNewClient(roomID, c)
+	// Virtual clients only talk to the member they were set up with
+	if c.Virtual {
+		return nil
+	}
+
+	// Notify other clients in the room; the join stands if other pods cannot
+	// be reached
+	if err := rm.notifyNewClient(roomID, c); err != nil {
+		log.Printf("Failed to announce %s to room %s: %v", c.ID, roomID, err)
+	}
+	// The room may already have members, here or on other pods
+	if asHost {
+		if others, _ := rm.GetParticipants(roomID, c.ID); len(others) > 0 {
+			rm.notifyHostChanged(roomID, c.ID)
+		}
+	}
+	return nil
 }
 
-// LeaveRoom handles a client leaving a room
-func (rm *RoomManager) LeaveRoom(c *model.Client) error {
-	room, err := rm.roomRepo.GetByClientID(c.ID)
+// JoinedMessage confirms a join to a client and carries the state of the room
+func (rm *RoomManager) JoinedMessage(c *model.Client, roomID string) *model.Message {
+	payload, _ := json.Marshal(rm.roomState(c, roomID))
+	return &model.Message{
+		Type:    model.MessageTypeJoined,
+		Payload: payload,
+	}
+}
+
+// roomState describes a room to a client that just entered it
+func (rm *RoomManager) roomState(c *model.Client, roomID string) map[string]interface{} {
+	participants, err := rm.GetParticipants(roomID, c.ID)
 	if err != nil {
-		return err
+		log.Printf("Failed to list participants of room %s: %v", roomID, err)
+		participants = []model.Participant{}
+	}
+	body := map[string]interface{}{
+		"room_id":      roomID,
+		"participants": participants,
+	}
+	if room, err := rm.roomRepo.GetByClientID(c.ID); err == nil {
+		body["host_id"] = room.HostID
+		body["locked"] = room.Locked
+		if room.ParentID != "" {
+			body["parent_room_id"] = room.ParentID
+		}
+		if deadline, _ := roomDeadline(room); !deadline.IsZero() {
+			body["expires_at"] = deadline.UTC()
+		}
+		if room.Persistent() {
+			body["persistent"] = true
+			body["capacity"] = room.Config.Capacity
+			body["topology"] = room.Config.Topology
+			body["recording_allowed"] = room.Config.RecordingAllowed
+		}
+	}
+	if config.Chat.Enabled {
+		history, err := rm.ChatHistory(roomID)
+		if err != nil {
+			log.Printf("Failed to load chat history of room %s: %v", roomID, err)
+		}
+		if history == nil {
+			history = []*model.ChatMessage{}
+		}
+		body["chat_history"] = history
+	}
+	return body
+}
+
+// LeaveRoom handles a client leaving a room, or the lobby it waits in
+func (rm *RoomManager) LeaveRoom(c *model.Client) error {
+	if rm.leaveLobby(c) {
+		return nil
 	}
 
-	// Notify other clients before removing
-	go rm.notifyLeaveClient(room.ID, c)
+	wasHost := false
+	if room, err := rm.roomRepo.GetByClientID(c.ID); err == nil {
+		wasHost = room.HostID == c.ID
+	}
 
-	// Remove client from room
-	delete(room.Clients, c.ID)
+	// Remove client from room; the room is deleted once empty
+	roomID, deleted, err := rm.roomRepo.RemoveClient(c.ID)
+	if err != nil {
+		return err
+	}
+	rm.left(c, roomID, deleted, wasHost)
+	return nil
+}
 
-	// If room is empty, delete it
-	if len(room.Clients) == 0 {
-		if err := rm.roomRepo.Delete(room.ID); err != nil {
-			log.Printf("Error deleting empty room %s: %v", room.ID, err)
-		} else {
-			log.Printf("Deleted empty room: %s", room.ID)
+// left tells a room that a client left it, passes on its host role and
+// closes the lobby of a room nobody is left in
+func (rm *RoomManager) left(c *model.Client, roomID string, deleted, wasHost bool) {
+	if deleted {
+		log.Printf("Deleted empty room: %s", roomID)
+		rm.chatRepo.Forget(roomID)
+	}
+	if remaining, _ := rm.roomRepo.Participants(roomID); len(remaining) == 0 {
+		// Persistent rooms are kept, but nobody is left to admit clients
+		rm.closeLobby(roomID)
+		if err := rm.publishLobby(roomID, "", lobbyClose, ""); err != nil {
+			log.Printf("Failed to publish lobby close of room %s: %v", roomID, err)
 		}
 	} else {
-		if _, err := rm.roomRepo.Update(room); err != nil {
-			return err
+		// Notify the remaining clients, on this pod and on others
+		rm.notifyLeaveClient(roomID, c)
+		if room, err := rm.roomRepo.Get(roomID); err == nil && wasHost && room.HostID != "" {
+			log.Printf("👑 Host of room %s passed from %s to %s", roomID, c.ID, room.HostID)
+			rm.notifyHostChanged(roomID, room.HostID)
 		}
 	}
 
-	log.Printf("Client %s left room %s", c.ID, room.ID)
-	return nil
+	log.Printf("Client %s left room %s", c.ID, roomID)
 }
 
-// notifyNewClient notifies all existing clients about a new client
-func (rm *RoomManager) notifyNewClient(roomID string, newClient *model.Client) error {
-	room, err := rm.roomRepo.Get(roomID)
-	if err != nil {
-		return err
-	}
+// UpdateProfile changes a client's profile and announces it to the members
+// of its room, including those on other pods
+func (rm *RoomManager) UpdateProfile(c *model.Client, profile model.Profile) error {
+	c.SetProfile(profile)
 
-	payload, _ := json.Marshal(map[string]string{"client_id": newClient.ID})
-	msg := &model.Message{
-		Type:    model.MessageTypeNewClient,
+	payload, _ := json.Marshal(c.Participant())
+	return rm.announce(c, &model.Message{
+		Type:    model.MessageTypeUpdateProfile,
 		Payload: payload,
-	}
+	})
+}
 
-	for _, client := range room.Clients {
-		if client.ID != newClient.ID {
-			select {
-			case client.Send <- msg:
-			default:
-				log.Printf("Failed to send new client notification to %s", client.ID)
-			}
-		}
-	}
+// UpdateState changes a client's media state and announces it to the members
+// of its room, including those on other pods
+func (rm *RoomManager) UpdateState(c *model.Client, state model.MediaState) error {
+	c.SetState(state)
 
-	return nil
+	// The whole participant, so that transports with typed peers (gRPC) do
+	// not present an update as a member without a name
+	payload, _ := json.Marshal(c.Participant())
+	return rm.announce(c, &model.Message{
+		Type:    model.MessageTypeStateUpdate,
+		Payload: payload,
+	})
 }
 
-// notifyLeaveClient notifies all clients about a client leaving
-func (rm *RoomManager) notifyLeaveClient(roomID string, leavingClient *model.Client) error {
-	room, err := rm.roomRepo.Get(roomID)
+// announce stores a client's profile and state for the participant lists of
+// late joiners and sends a message about the change to the other members of
+// its room. Outside a room there is nobody to tell; the client's profile and
+// state are announced when it joins.
+func (rm *RoomManager) announce(c *model.Client, msg *model.Message) error {
+	roomID, _, err := rm.roomRepo.FindClient(c.ID)
+	if err == repository.ErrNotFound || c.Virtual {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
+	if err := rm.roomRepo.UpdateParticipant(c); err != nil && err != repository.ErrNotFound {
+		return err
+	}
+	return rm.sendToRoom(roomID, c.ID, c.ID, msg)
+}
+
+// notifyNewClient tells the other members of a room, including those on
+// other pods, about a new member and its profile
+func (rm *RoomManager) notifyNewClient(roomID string, newClient *model.Client) error {
+	payload, _ := json.Marshal(newClient.Participant())
+	return rm.sendToRoom(roomID, newClient.ID, newClient.ID, &model.Message{
+		Type:    model.MessageTypeNewClient,
+		Payload: payload,
+	})
+}
 
+// notifyLeaveClient tells the remaining members of a room, including those
+// on other pods, that a client left
+func (rm *RoomManager) notifyLeaveClient(roomID string, leavingClient *model.Client) error {
 	payload, _ := json.Marshal(map[string]string{"client_id": leavingClient.ID})
-	msg := &model.Message{
+	return rm.sendToRoom(roomID, leavingClient.ID, leavingClient.ID, &model.Message{
 		Type:    model.MessageTypeLeaveClient,
 		Payload: payload,
-	}
-
-	for _, client := range room.Clients {
-		if client.ID != leavingClient.ID {
-			select {
-			case client.Send <- msg:
-			default:
-				log.Printf("Failed to send leave notification to %s", client.ID)
-			}
-		}
-	}
-
-	return nil
+	})
 }
 
 // GetClientByID returns a client by ID (for clustering service)
 func (rm *RoomManager) GetClientByID(clientID string) (*model.Client, error) {
-	room, err := rm.roomRepo.GetByClientID(clientID)
+	_, client, err := rm.roomRepo.FindClient(clientID)
+	return client, err
+}
+
+// IsMember reports whether a client is in a room on any pod
+func (rm *RoomManager) IsMember(clientID string) bool {
+	ok, err := rm.roomRepo.IsMember(clientID)
+	if err != nil {
+		log.Printf("Failed to look up membership of %s: %v", clientID, err)
+	}
+	return ok
+}
+
+// GetParticipants returns the members of a room on every pod as announced
+// to late joiners, except excludeClientID
+func (rm *RoomManager) GetParticipants(roomID, excludeClientID string) ([]model.Participant, error) {
+	members, err := rm.roomRepo.Participants(roomID)
 	if err != nil {
 		return nil, err
 	}
 
-	client, ok := room.Clients[clientID]
-	if !ok {
-		return nil, repository.ErrNotFound
+	participants := make([]model.Participant, 0, len(members))
+	for _, p := range members {
+		if p.ClientID != excludeClientID {
+			participants = append(participants, p)
+		}
 	}
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].ClientID < participants[j].ClientID
+	})
+	return participants, nil
+}
 
-	return client, nil
+// GetClients returns the clients currently in a room
+func (rm *RoomManager) GetClients(roomID string) ([]*model.Client, error) {
+	return rm.roomRepo.Clients(roomID)
+}
+
+// GetRoom returns a snapshot of a room
+func (rm *RoomManager) GetRoom(roomID string) (*model.Room, error) {
+	return rm.roomRepo.Get(roomID)
+}
+
+// GetRooms returns the rooms that have members on this pod
+func (rm *RoomManager) GetRooms() ([]*model.Room, error) {
+	return rm.roomRepo.List()
 }
 
 // GetRoomByClientID returns a room containing the specified client (for clustering service)
@@ -151,13 +321,13 @@ func (rm *RoomManager) GetRoomByClientID(clientID string) (*model.Room, error) {
 
 // TransferSDPOffer transfers an SDP offer from one client to another
 func (rm *RoomManager) TransferSDPOffer(senderClient *model.Client, sdp *model.SDP, targetClientID string) error {
-	room, err := rm.roomRepo.GetByClientID(senderClient.ID)
+	roomID, _, err := rm.roomRepo.FindClient(senderClient.ID)
 	if err != nil {
 		return err
 	}
 
-	targetClient, ok := room.Clients[targetClientID]
-	if !ok {
+	targetClient, err := rm.roomRepo.GetClient(roomID, targetClientID)
+	if err != nil {
 		// Target client not in local room, publish to Redis for other pods
 		log.Printf("Target client %s not found locally, publishing to Redis", targetClientID)
 		return rm.publishSDPOfferToRedis(senderClient.ID, targetClientID, sdp)
@@ -185,13 +355,13 @@ func (rm *RoomManager) TransferSDPOffer(senderClient *model.Client, sdp *model.S
 
 // TransferSDPAnswer transfers an SDP answer from one client to another
 func (rm *RoomManager) TransferSDPAnswer(senderClient *model.Client, sdp *model.SDP, targetClientID string) error {
-	room, err := rm.roomRepo.GetByClientID(senderClient.ID)
+	roomID, _, err := rm.roomRepo.FindClient(senderClient.ID)
 	if err != nil {
 		return err
 	}
 
-	targetClient, ok := room.Clients[targetClientID]
-	if !ok {
+	targetClient, err := rm.roomRepo.GetClient(roomID, targetClientID)
+	if err != nil {
 		// Target client not in local room, publish to Redis for other pods
 		log.Printf("Target client %s not found locally, publishing to Redis", targetClientID)
 		return rm.publishSDPAnswerToRedis(senderClient.ID, targetClientID, sdp)
@@ -219,13 +389,13 @@ func (rm *RoomManager) TransferSDPAnswer(senderClient *model.Client, sdp *model.
 
 // TransferIceCandidate transfers an ICE candidate from one client to another
 func (rm *RoomManager) TransferIceCandidate(senderClient *model.Client, iceCandidate *model.IceCandidate, targetClientID string) error {
-	room, err := rm.roomRepo.GetByClientID(senderClient.ID)
+	roomID, _, err := rm.roomRepo.FindClient(senderClient.ID)
 	if err != nil {
 		return err
 	}
 
-	targetClient, ok := room.Clients[targetClientID]
-	if !ok {
+	targetClient, err := rm.roomRepo.GetClient(roomID, targetClientID)
+	if err != nil {
 		// Target client not in local room, publish to Redis for other pods
 		log.Printf("Target client %s not found locally, publishing to Redis", targetClientID)
 		return rm.publishIceCandidateToRedis(senderClient.ID, targetClientID, iceCandidate)
@@ -268,8 +438,7 @@ func (rm *RoomManager) publishSDPOfferToRedis(senderClientID, targetClientID str
 		Payload:        payload,
 	}
 
-	msgBytes, _ := json.Marshal(redisMsg)
-	return config.Rdb.Publish(config.Ctx, string(model.RedisMessageTypeSDPOffer), msgBytes).Err()
+	return rm.publishToRedis(redisMsg)
 }
 
 func (rm *RoomManager) publishSDPAnswerToRedis(senderClientID, targetClientID string, sdp *model.SDP) error {
@@ -285,8 +454,7 @@ func (rm *RoomManager) publishSDPAnswerToRedis(senderClientID, targetClientID st
 		Payload:        payload,
 	}
 
-	msgBytes, _ := json.Marshal(redisMsg)
-	return config.Rdb.Publish(config.Ctx, string(model.RedisMessageTypeSDPAnswer), msgBytes).Err()
+	return rm.publishToRedis(redisMsg)
 }
 
 func (rm *RoomManager) publishIceCandidateToRedis(senderClientID, targetClientID string, iceCandidate *model.IceCandidate) error {
@@ -304,6 +472,19 @@ func (rm *RoomManager) publishIceCandidateToRedis(senderClientID, targetClientID
 		Payload:        payload,
 	}
 
-	msgBytes, _ := json.Marshal(redisMsg)
-	return config.Rdb.Publish(config.Ctx, string(model.RedisMessageTypeIceCandidate), msgBytes).Err()
+	return rm.publishToRedis(redisMsg)
+}
+
+// publishToRedis publishes a message on the channel named after its type.
+// Without Redis there are no other pods, so the target cannot be reached.
+func (rm *RoomManager) publishToRedis(redisMsg *model.RedisMessage) error {
+	if config.Rdb == nil {
+		return repository.ErrNotFound
+	}
+	redisMsg.Node = config.NodeID
+	msgBytes, err := redisMsg.Encode(config.RedisEncoding)
+	if err != nil {
+		return err
+	}
+	return config.Rdb.Publish(config.Ctx, string(redisMsg.Type), msgBytes).Err()
 }
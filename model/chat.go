@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// ChatMessage is a text message posted to a room
+type ChatMessage struct {
+	ID       string     `json:"message_id"`
+	ClientID string     `json:"client_id"`
+	Name     string     `json:"name"`
+	Text     string     `json:"text"`
+	SentAt   time.Time  `json:"sent_at"`
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+}
+
+// NewChatMessage creates a chat message from a client with a unique, time-ordered ID
+func NewChatMessage(c *Client, text string) *ChatMessage {
+	return &ChatMessage{
+		ID:       xid.New().String(),
+		ClientID: c.ID,
+		Name:     c.Profile().Name,
+		Text:     text,
+		SentAt:   time.Now().UTC(),
+	}
+}
@@ -6,13 +6,33 @@ import "encoding/json"
 type MessageType string
 
 const (
-	MessageTypeNotifyClientID MessageType = "notify-client-id"
-	MessageTypeNewClient      MessageType = "new-client"
-	MessageTypeLeaveClient    MessageType = "leave-client"
-	MessageTypeSDPOffer       MessageType = "offer"
-	MessageTypeSDPAnswer      MessageType = "answer"
-	MessageTypeIceCandidate   MessageType = "ice-candidate"
-	MessageTypeError          MessageType = "error"
+	MessageTypeNotifyClientID     MessageType = "notify-client-id"
+	MessageTypeNewClient          MessageType = "new-client"
+	MessageTypeLeaveClient        MessageType = "leave-client"
+	MessageTypeSDPOffer           MessageType = "offer"
+	MessageTypeSDPAnswer          MessageType = "answer"
+	MessageTypeIceCandidate       MessageType = "ice-candidate"
+	MessageTypeError              MessageType = "error"
+	MessageTypeBroadcast          MessageType = "broadcast"
+	MessageTypeDirect             MessageType = "direct"
+	MessageTypeJoined             MessageType = "joined"
+	MessageTypeChat               MessageType = "chat"
+	MessageTypeChatEdit           MessageType = "chat-edit"
+	MessageTypeChatDelete         MessageType = "chat-delete"
+	MessageTypeUpdateProfile      MessageType = "update-profile"
+	MessageTypeStateUpdate        MessageType = "state-update"
+	MessageTypeKicked             MessageType = "kicked"
+	MessageTypeMuteRequest        MessageType = "mute-request"
+	MessageTypeRoomLocked         MessageType = "room-locked"
+	MessageTypeHostChanged        MessageType = "host-changed"
+	MessageTypeWaiting            MessageType = "waiting"
+	MessageTypeAdmissionRequest   MessageType = "admission-request"
+	MessageTypeAdmissionCancelled MessageType = "admission-cancelled"
+	MessageTypeDenied             MessageType = "denied"
+	MessageTypeMovedToRoom        MessageType = "moved-to-room"
+	MessageTypeRoomExpiring       MessageType = "room-expiring"
+	MessageTypeRoomClosed         MessageType = "room-closed"
+	MessageTypeIdleTimeout        MessageType = "idle-timeout"
 )
 
 // Message represents a signaling message
@@ -21,6 +41,16 @@ type Message struct {
 	Payload json.RawMessage `json:"payload"`
 }
 
+// Disconnects reports whether the transport closes the connection after
+// delivering the message
+func (m *Message) Disconnects() bool {
+	switch m.Type {
+	case MessageTypeKicked, MessageTypeRoomClosed, MessageTypeIdleTimeout:
+		return true
+	}
+	return false
+}
+
 // SDP represents WebRTC Session Description Protocol data
 type SDP struct {
 	Type string `json:"type"`
@@ -29,21 +59,34 @@ type SDP struct {
 
 // IceCandidate represents WebRTC ICE candidate data
 type IceCandidate struct {
-	Candidate      string  `json:"candidate"`
-	SdpMid         *string `json:"sdpMid,omitempty"`
-	SdpMLineIndex  *uint16 `json:"sdpMLineIndex,omitempty"`
-	ClientID       string  `json:"client_id"`
+	Candidate     string  `json:"candidate"`
+	SdpMid        *string `json:"sdpMid,omitempty"`
+	SdpMLineIndex *uint16 `json:"sdpMLineIndex,omitempty"`
+	ClientID      string  `json:"client_id"`
+}
+
+// IceServer is an entry of RTCConfiguration.iceServers
+type IceServer struct {
+	URLs       []string `json:"urls"`
+	Username   string   `json:"username,omitempty"`
+	Credential string   `json:"credential,omitempty"`
 }
 
 // RedisMessageType defines the type of Redis Pub/Sub message for clustering
 type RedisMessageType string
 
 const (
-	RedisMessageTypeSDPOffer      RedisMessageType = "webrtc:offer"
-	RedisMessageTypeSDPAnswer     RedisMessageType = "webrtc:answer"
-	RedisMessageTypeIceCandidate  RedisMessageType = "webrtc:ice"
-	RedisMessageTypeNewClient     RedisMessageType = "webrtc:new_client"
-	RedisMessageTypeLeaveClient   RedisMessageType = "webrtc:leave_client"
+	RedisMessageTypeSDPOffer     RedisMessageType = "webrtc:offer"
+	RedisMessageTypeSDPAnswer    RedisMessageType = "webrtc:answer"
+	RedisMessageTypeIceCandidate RedisMessageType = "webrtc:ice"
+	RedisMessageTypeNewClient    RedisMessageType = "webrtc:new_client"
+	RedisMessageTypeLeaveClient  RedisMessageType = "webrtc:leave_client"
+	RedisMessageTypeBroadcast    RedisMessageType = "webrtc:broadcast"
+	RedisMessageTypeDirect       RedisMessageType = "webrtc:direct"
+	RedisMessageTypeMove         RedisMessageType = "webrtc:move"
+	RedisMessageTypeExpire       RedisMessageType = "webrtc:expire"
+	RedisMessageTypeLobby        RedisMessageType = "webrtc:lobby"
+	RedisMessageTypeSSE          RedisMessageType = "webrtc:sse"
 )
 
 // RedisMessage represents a message sent through Redis Pub/Sub
@@ -53,4 +96,11 @@ type RedisMessage struct {
 	TargetClientID string           `json:"target_client_id"`
 	RoomID         string           `json:"room_id,omitempty"`
 	Payload        json.RawMessage  `json:"payload"`
+	// MessageType is the client message type of a room-wide or direct
+	// message (webrtc:broadcast, webrtc:direct); empty means broadcast or
+	// direct. On webrtc:expire it is room-expiring or room-closed.
+	MessageType MessageType `json:"message_type,omitempty"`
+	// Node is the pod that published the message, which already delivered
+	// it to its own members
+	Node string `json:"node,omitempty"`
 }
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Encodings of RedisMessage on the cluster bus
+const (
+	RedisEncodingJSON   = "json"
+	RedisEncodingBinary = "binary"
+)
+
+// Field numbers of the binary RedisMessage encoding. New fields must use new
+// numbers so that pods running different versions can still talk.
+const (
+	redisFieldType           protowire.Number = 1
+	redisFieldSenderClientID protowire.Number = 2
+	redisFieldTargetClientID protowire.Number = 3
+	redisFieldRoomID         protowire.Number = 4
+	redisFieldPayload        protowire.Number = 5
+	redisFieldMessageType    protowire.Number = 6
+	redisFieldNode           protowire.Number = 7
+)
+
+var errInvalidRedisMessage = errors.New("invalid binary redis message")
+
+// Encode serializes the message for the cluster bus in the given encoding
+func (m *RedisMessage) Encode(encoding string) ([]byte, error) {
+	if encoding == RedisEncodingJSON {
+		return json.Marshal(m)
+	}
+	return m.MarshalBinary()
+}
+
+// MarshalBinary encodes the message in protobuf wire format, which avoids
+// escaping the JSON payload a second time
+func (m *RedisMessage) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(m.Type)+len(m.SenderClientID)+len(m.TargetClientID)+len(m.RoomID)+len(m.Payload)+len(m.MessageType)+len(m.Node)+16)
+	b = appendStringField(b, redisFieldType, string(m.Type))
+	b = appendStringField(b, redisFieldSenderClientID, m.SenderClientID)
+	b = appendStringField(b, redisFieldTargetClientID, m.TargetClientID)
+	b = appendStringField(b, redisFieldRoomID, m.RoomID)
+	if len(m.Payload) > 0 {
+		b = protowire.AppendTag(b, redisFieldPayload, protowire.BytesType)
+		b = protowire.AppendBytes(b, m.Payload)
+	}
+	b = appendStringField(b, redisFieldMessageType, string(m.MessageType))
+	b = appendStringField(b, redisFieldNode, m.Node)
+	return b, nil
+}
+
+// UnmarshalBinary decodes a message written by MarshalBinary; unknown fields are skipped
+func (m *RedisMessage) UnmarshalBinary(data []byte) error {
+	*m = RedisMessage{}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("%w: %v", errInvalidRedisMessage, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, data); n < 0 {
+				return fmt.Errorf("%w: %v", errInvalidRedisMessage, protowire.ParseError(n))
+			}
+			data = data[n:]
+			continue
+		}
+
+		v, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return fmt.Errorf("%w: %v", errInvalidRedisMessage, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch num {
+		case redisFieldType:
+			m.Type = RedisMessageType(v)
+		case redisFieldSenderClientID:
+			m.SenderClientID = string(v)
+		case redisFieldTargetClientID:
+			m.TargetClientID = string(v)
+		case redisFieldRoomID:
+			m.RoomID = string(v)
+		case redisFieldPayload:
+			m.Payload = append(json.RawMessage(nil), v...)
+		case redisFieldMessageType:
+			m.MessageType = MessageType(v)
+		case redisFieldNode:
+			m.Node = string(v)
+		}
+	}
+	return nil
+}
+
+// DecodeRedisMessage decodes a message from the cluster bus in either
+// encoding, so pods can be switched between encodings one at a time
+func DecodeRedisMessage(data []byte) (*RedisMessage, error) {
+	var m RedisMessage
+	// A binary message starts with a field tag, never with '{'
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		return &m, nil
+	}
+	if err := m.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func appendStringField(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
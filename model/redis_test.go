@@ -0,0 +1,116 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testRedisMessage() *RedisMessage {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"client_id": "d0qkvb4h7ojs47rulv50",
+		"sdp":       "v=0\r\no=- 4611731400430051336 2 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n" + strings.Repeat("a=rtcp-fb:96 nack pli\r\n", 60),
+	})
+	return &RedisMessage{
+		Type:           RedisMessageTypeDirect,
+		SenderClientID: "d0qkvb4h7ojs47rulv50",
+		TargetClientID: "d0qkvb4h7ojs47rulv60",
+		RoomID:         "room",
+		Payload:        payload,
+		MessageType:    MessageTypeSDPOffer,
+		Node:           "d0qkvb4h7ojs47rulv70",
+	}
+}
+
+func TestDecodeRedisMessageRoundTrip(t *testing.T) {
+	messages := map[string]*RedisMessage{
+		"full":  testRedisMessage(),
+		"empty": {Type: RedisMessageTypeLeaveClient, SenderClientID: "d0qkvb4h7ojs47rulv50"},
+	}
+	for name, want := range messages {
+		for _, encoding := range []string{RedisEncodingJSON, RedisEncodingBinary} {
+			data, err := want.Encode(encoding)
+			if err != nil {
+				t.Fatalf("%s/%s: encode: %v", name, encoding, err)
+			}
+			got, err := DecodeRedisMessage(data)
+			if err != nil {
+				t.Fatalf("%s/%s: decode: %v", name, encoding, err)
+			}
+			if encoding == RedisEncodingJSON && want.Payload == nil {
+				// JSON has no way to tell a missing payload from null
+				got.Payload = nil
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s/%s: got %+v, want %+v", name, encoding, got, want)
+			}
+		}
+	}
+}
+
+func TestDecodeRedisMessageSkipsUnknownFields(t *testing.T) {
+	want := testRedisMessage()
+	data, _ := want.MarshalBinary()
+	// Fields 14 (varint) and 15 (bytes), as a newer pod might add
+	data = append(data, 14<<3|0, 42, 15<<3|2, 3, 'n', 'e', 'w')
+
+	got, err := DecodeRedisMessage(data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeRedisMessageCorrupt(t *testing.T) {
+	binary, _ := testRedisMessage().MarshalBinary()
+	inputs := map[string][]byte{
+		"truncated binary": binary[:len(binary)/2],
+		"bad length":       {byte(redisFieldRoomID)<<3 | 2, 0xff, 0xff, 0xff, 0xff, 0x0f},
+		"bad tag":          {0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"field zero":       {0<<3 | 2, 1, 'x'},
+		"truncated JSON":   []byte(`{"type":"webrtc:direct","room_id":`),
+		"wrong JSON type":  []byte(`{"type":42}`),
+	}
+	for name, data := range inputs {
+		if msg, err := DecodeRedisMessage(data); err == nil {
+			t.Errorf("%s: decoded %+v", name, msg)
+		}
+	}
+}
+
+func BenchmarkRedisMessageEncode(b *testing.B) {
+	msg := testRedisMessage()
+	for _, encoding := range []string{RedisEncodingJSON, RedisEncodingBinary} {
+		b.Run(encoding, func(b *testing.B) {
+			data, _ := msg.Encode(encoding)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := msg.Encode(encoding); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/msg")
+		})
+	}
+}
+
+func BenchmarkRedisMessageDecode(b *testing.B) {
+	msg := testRedisMessage()
+	for _, encoding := range []string{RedisEncodingJSON, RedisEncodingBinary} {
+		b.Run(encoding, func(b *testing.B) {
+			data, _ := msg.Encode(encoding)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := DecodeRedisMessage(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/msg")
+		})
+	}
+}
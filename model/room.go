@@ -1,12 +1,58 @@
 package model
 
-import "github.com/rs/xid"
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/xid"
+)
 
 // Room represents a WebRTC signaling room
 type Room struct {
 	ID      string
 	Name    string
 	Clients map[string]*Client
+	// HostID is the member allowed to moderate the room
+	HostID string
+	// Locked rooms only admit clients that claim the host role
+	Locked bool
+	// ParentID is the room a breakout room was split from
+	ParentID string
+	// StartedAt is when the room got its first member on this pod
+	StartedAt time.Time
+	// Config is set on persistent rooms, which are kept when empty. It is
+	// replaced as a whole and must not be modified.
+	Config *RoomConfig
+}
+
+// Topologies a persistent room can ask its clients to use
+const (
+	TopologyMesh = "mesh" // every member connects to every other member
+	TopologySFU  = "sfu"  // members connect to a media server
+)
+
+// RoomConfig configures a persistent room created through the admin API.
+// Rooms without one are created on first join and deleted once empty.
+type RoomConfig struct {
+	// Capacity limits the members of the room on all pods; 0 is unlimited
+	Capacity int `json:"capacity"`
+	// PasswordHash is set by SetPassword; clients without a host token must
+	// send the password to join
+	PasswordHash     string    `json:"password_hash,omitempty"`
+	Topology         string    `json:"topology"`
+	RecordingAllowed bool      `json:"recording_allowed"`
+	CreatedAt        time.Time `json:"created_at"`
+	// ExpiresAt is when the room is closed for good; the zero time never
+	ExpiresAt time.Time `json:"expires_at"`
+	// MaxDuration closes the room that long after it got its first member,
+	// overriding ROOM_MAX_DURATION; it stays configured for the next meeting
+	MaxDuration time.Duration `json:"max_duration,omitempty"`
 }
 
 // NewRoom creates a new room with the given name
@@ -18,18 +64,207 @@ func NewRoom(name string) *Room {
 	}
 }
 
+// Snapshot returns a copy of the room that is safe to read without locking
+func (r *Room) Snapshot() *Room {
+	clients := make(map[string]*Client, len(r.Clients))
+	for id, c := range r.Clients {
+		clients[id] = c
+	}
+	return &Room{
+		ID:        r.ID,
+		Name:      r.Name,
+		Clients:   clients,
+		HostID:    r.HostID,
+		Locked:    r.Locked,
+		ParentID:  r.ParentID,
+		StartedAt: r.StartedAt,
+		Config:    r.Config,
+	}
+}
+
+// Equal reports whether two configurations have the same settings; nil is
+// the configuration of an ad-hoc room. Times are compared as instants since
+// they lose their location when stored.
+func (c *RoomConfig) Equal(other *RoomConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return c.Capacity == other.Capacity &&
+		c.PasswordHash == other.PasswordHash &&
+		c.Topology == other.Topology &&
+		c.RecordingAllowed == other.RecordingAllowed &&
+		c.CreatedAt.Equal(other.CreatedAt) &&
+		c.ExpiresAt.Equal(other.ExpiresAt) &&
+		c.MaxDuration == other.MaxDuration
+}
+
+// SetPassword stores a salted hash of a room password; an empty password
+// removes it
+func (c *RoomConfig) SetPassword(password string) {
+	if password == "" {
+		c.PasswordHash = ""
+		return
+	}
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	c.PasswordHash = base64.RawURLEncoding.EncodeToString(salt) + ":" + passwordDigest(salt, password)
+}
+
+// CheckPassword reports whether password opens the room
+func (c *RoomConfig) CheckPassword(password string) bool {
+	if c.PasswordHash == "" {
+		return true
+	}
+	encodedSalt, digest, ok := strings.Cut(c.PasswordHash, ":")
+	if !ok {
+		return false
+	}
+	salt, err := base64.RawURLEncoding.DecodeString(encodedSalt)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(digest), []byte(passwordDigest(salt, password))) == 1
+}
+
+// Expired reports whether the room was closed for good at now
+func (c *RoomConfig) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+// Persistent reports whether the room is kept when empty
+func (r *Room) Persistent() bool {
+	return r.Config != nil && !r.Config.Expired(time.Now())
+}
+
+func passwordDigest(salt []byte, password string) string {
+	sum := sha256.Sum256(append(append([]byte(nil), salt...), password...))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
 // Client represents a connected WebRTC client
 type Client struct {
 	ID   string
-	Name string
 	Send chan *Message
+	// Virtual clients stand in for HTTP sessions (WHIP/WHEP); they exchange
+	// SDP with a single room member and are not announced with new-client
+	Virtual bool
+
+	mu      sync.RWMutex
+	profile Profile
+	state   MediaState
+
+	lastActive atomic.Int64 // Unix nanoseconds of the last message from the client
+
+	disconnect     chan struct{}
+	disconnectOnce sync.Once
+	farewell       *Message // the message of Disconnect
+}
+
+// Profile is how a client presents itself to the other members of its room
+type Profile struct {
+	Name     string            `json:"name"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// MediaState is what a client tells the other members about its media and
+// presence, so they can show it before any media arrives
+type MediaState struct {
+	AudioMuted    bool `json:"audio_muted"`
+	VideoMuted    bool `json:"video_muted"`
+	ScreenSharing bool `json:"screen_sharing"`
+	Speaking      bool `json:"speaking"`
+	HandRaised    bool `json:"hand_raised"`
+}
+
+// Participant is a room member as announced to the other members
+type Participant struct {
+	ClientID string `json:"client_id"`
+	Profile
+	State MediaState `json:"state"`
 }
 
 // NewClient creates a new client with a unique ID
 func NewClient(name string) *Client {
-	return &Client{
-		ID:   xid.New().String(),
-		Name: name,
-		Send: make(chan *Message, 16),
+	c := &Client{
+		ID:         xid.New().String(),
+		Send:       make(chan *Message, 16),
+		profile:    Profile{Name: name},
+		disconnect: make(chan struct{}),
 	}
+	c.Touch()
+	return c
+}
+
+// NewVirtualClient creates a client for an HTTP signaling session
+func NewVirtualClient(name string) *Client {
+	c := NewClient(name)
+	c.Virtual = true
+	return c
+}
+
+// Profile returns the client's current profile. The metadata map is shared
+// and must not be modified.
+func (c *Client) Profile() Profile {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.profile
+}
+
+// SetProfile replaces the client's profile
+func (c *Client) SetProfile(p Profile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.profile = p
+}
+
+// State returns the client's current media state
+func (c *Client) State() MediaState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state
+}
+
+// SetState replaces the client's media state
+func (c *Client) SetState(s MediaState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state = s
+}
+
+// Touch records that the client sent a message or another frame
+func (c *Client) Touch() {
+	c.lastActive.Store(time.Now().UnixNano())
+}
+
+// LastActive returns when the client last sent a message or another frame,
+// or connected
+func (c *Client) LastActive() time.Time {
+	return time.Unix(0, c.lastActive.Load())
+}
+
+// Disconnect asks the client's transport to deliver msg and close the
+// connection. Unlike a message on Send, it is not lost when the client does
+// not keep up. Only the first call has an effect.
+func (c *Client) Disconnect(msg *Message) {
+	c.disconnectOnce.Do(func() {
+		c.farewell = msg
+		close(c.disconnect)
+	})
+}
+
+// Disconnected is closed once Disconnect was called
+func (c *Client) Disconnected() <-chan struct{} {
+	return c.disconnect
+}
+
+// Farewell returns the message of Disconnect once Disconnected is closed
+func (c *Client) Farewell() *Message {
+	return c.farewell
+}
+
+// Participant returns the client as announced to the other members of its room
+func (c *Client) Participant() Participant {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return Participant{ClientID: c.ID, Profile: c.profile, State: c.state}
 }
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestRoomConfigEqual checks that a configuration equals its stored copy,
+// whose times lost their location, and differs from a changed one
+func TestRoomConfigEqual(t *testing.T) {
+	cfg := &RoomConfig{
+		Capacity:  10,
+		Topology:  TopologyMesh,
+		CreatedAt: time.Now().In(time.FixedZone("CEST", 2*60*60)),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	cfg.SetPassword("secret")
+	data, _ := json.Marshal(cfg)
+	var stored RoomConfig
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !cfg.Equal(&stored) {
+		t.Errorf("stored copy differs: %+v, %+v", cfg, stored)
+	}
+	changed := stored
+	changed.Capacity = 20
+	if cfg.Equal(&changed) {
+		t.Error("configuration with another capacity is equal")
+	}
+	var none *RoomConfig
+	if !none.Equal(nil) || none.Equal(cfg) || cfg.Equal(nil) {
+		t.Error("nil configurations are only equal to each other")
+	}
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// RoomRecord records a room being open on a node, from its first member
+// joining until the last one left
+type RoomRecord struct {
+	ID        string     `json:"id"`
+	RoomID    string     `json:"room_id"`
+	Node      string     `json:"node"`
+	CreatedAt time.Time  `json:"created_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+}
+
+// SessionRecord records a client's membership in a room
+type SessionRecord struct {
+	ID           string     `json:"id"`
+	RoomRecordID string     `json:"room_record_id"`
+	RoomID       string     `json:"room_id"`
+	ClientID     string     `json:"client_id"`
+	Name         string     `json:"name"`
+	Virtual      bool       `json:"virtual"`
+	Node         string     `json:"node"`
+	JoinedAt     time.Time  `json:"joined_at"`
+	LeftAt       *time.Time `json:"left_at,omitempty"`
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"errors"
+
+	"gosignaling/model"
+)
+
+// Chat defines the interface for the bounded chat history of rooms.
+// Implementations keep at most a fixed number of messages per room and drop
+// the oldest ones first.
+type Chat interface {
+	// Append adds a message to the history of a room
+	Append(roomID string, msg *model.ChatMessage) error
+	// Recent returns up to n of the latest messages of a room, oldest first
+	Recent(roomID string, n int) ([]*model.ChatMessage, error)
+	// Get returns a message from the history of a room
+	Get(roomID, messageID string) (*model.ChatMessage, error)
+	// Update replaces a message that is still in the history
+	Update(roomID string, msg *model.ChatMessage) error
+	// Delete removes a message from the history
+	Delete(roomID, messageID string) error
+	// Forget is called when a room becomes empty on this pod. Local stores
+	// drop the history; shared stores keep it for members on other pods.
+	Forget(roomID string) error
+}
+
+var (
+	ErrMessageNotFound = errors.New("message not found")
+)
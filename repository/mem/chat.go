@@ -0,0 +1,114 @@
+package mem
+
+import (
+	"sync"
+
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+type chatRepository struct {
+	mutex   sync.RWMutex
+	size    int
+	history map[string][]*model.ChatMessage // room ID -> messages, oldest first
+}
+
+// NewChatRepository creates an in-memory chat history keeping size messages per room
+func NewChatRepository(size int) repository.Chat {
+	return &chatRepository{
+		size:    size,
+		history: make(map[string][]*model.ChatMessage),
+	}
+}
+
+// Append adds a message and drops the oldest ones beyond the size limit
+func (r *chatRepository) Append(roomID string, msg *model.ChatMessage) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	messages := append(r.history[roomID], copyChatMessage(msg))
+	if over := len(messages) - r.size; over > 0 {
+		messages = append([]*model.ChatMessage(nil), messages[over:]...)
+	}
+	r.history[roomID] = messages
+	return nil
+}
+
+// Recent returns up to n of the latest messages, oldest first
+func (r *chatRepository) Recent(roomID string, n int) ([]*model.ChatMessage, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	messages := r.history[roomID]
+	if len(messages) > n {
+		messages = messages[len(messages)-n:]
+	}
+	recent := make([]*model.ChatMessage, 0, len(messages))
+	for _, msg := range messages {
+		recent = append(recent, copyChatMessage(msg))
+	}
+	return recent, nil
+}
+
+// Get returns a message from the history of a room
+func (r *chatRepository) Get(roomID, messageID string) (*model.ChatMessage, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	i := r.indexLocked(roomID, messageID)
+	if i < 0 {
+		return nil, repository.ErrMessageNotFound
+	}
+	return copyChatMessage(r.history[roomID][i]), nil
+}
+
+// Update replaces a message that is still in the history
+func (r *chatRepository) Update(roomID string, msg *model.ChatMessage) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	i := r.indexLocked(roomID, msg.ID)
+	if i < 0 {
+		return repository.ErrMessageNotFound
+	}
+	r.history[roomID][i] = copyChatMessage(msg)
+	return nil
+}
+
+// Delete removes a message from the history
+func (r *chatRepository) Delete(roomID, messageID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	i := r.indexLocked(roomID, messageID)
+	if i < 0 {
+		return repository.ErrMessageNotFound
+	}
+	messages := r.history[roomID]
+	r.history[roomID] = append(messages[:i:i], messages[i+1:]...)
+	return nil
+}
+
+// Forget drops the history of a room
+func (r *chatRepository) Forget(roomID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.history, roomID)
+	return nil
+}
+
+// indexLocked returns the position of a message or -1; callers must hold the lock
+func (r *chatRepository) indexLocked(roomID, messageID string) int {
+	for i, msg := range r.history[roomID] {
+		if msg.ID == messageID {
+			return i
+		}
+	}
+	return -1
+}
+
+func copyChatMessage(msg *model.ChatMessage) *model.ChatMessage {
+	c := *msg
+	return &c
+}
@@ -2,20 +2,23 @@ package mem
 
 import (
 	"sync"
+	"time"
 
 	"gosignaling/model"
 	"gosignaling/repository"
 )
 
 type roomRepository struct {
-	mutex sync.RWMutex
-	rooms map[string]*model.Room
+	mutex   sync.RWMutex
+	rooms   map[string]*model.Room
+	clients map[string]string // client ID -> room ID
 }
 
 // NewRoomRepository creates a new in-memory room repository
 func NewRoomRepository() repository.Room {
 	return &roomRepository{
-		rooms: make(map[string]*model.Room),
+		rooms:   make(map[string]*model.Room),
+		clients: make(map[string]string),
 	}
 }
 
@@ -28,7 +31,7 @@ func (r *roomRepository) Get(roomID string) (*model.Room, error) {
 	if !ok {
 		return nil, repository.ErrNotFound
 	}
-	return room, nil
+	return room.Snapshot(), nil
 }
 
 // Create creates a new room
@@ -36,7 +39,7 @@ func (r *roomRepository) Create(room *model.Room) (*model.Room, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	r.rooms[room.ID] = room
+	r.putLocked(room.Snapshot())
 	return room, nil
 }
 
@@ -48,7 +51,7 @@ func (r *roomRepository) Update(room *model.Room) (*model.Room, error) {
 	if _, ok := r.rooms[room.ID]; !ok {
 		return nil, repository.ErrNotFound
 	}
-	r.rooms[room.ID] = room
+	r.putLocked(room.Snapshot())
 	return room, nil
 }
 
@@ -57,10 +60,11 @@ func (r *roomRepository) Delete(roomID string) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	if _, ok := r.rooms[roomID]; !ok {
+	room, ok := r.rooms[roomID]
+	if !ok {
 		return repository.ErrNotFound
 	}
-	delete(r.rooms, roomID)
+	r.deleteLocked(room)
 	return nil
 }
 
@@ -69,10 +73,303 @@ func (r *roomRepository) GetByClientID(clientID string) (*model.Room, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
+	room, ok := r.rooms[r.clients[clientID]]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return room.Snapshot(), nil
+}
+
+// List returns snapshots of all rooms
+func (r *roomRepository) List() ([]*model.Room, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	rooms := make([]*model.Room, 0, len(r.rooms))
+	for _, room := range r.rooms {
+		rooms = append(rooms, room.Snapshot())
+	}
+	return rooms, nil
+}
+
+// ListPersistent returns snapshots of the persistent rooms
+func (r *roomRepository) ListPersistent() ([]*model.Room, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var rooms []*model.Room
 	for _, room := range r.rooms {
-		if _, ok := room.Clients[clientID]; ok {
-			return room, nil
+		if room.Persistent() {
+			rooms = append(rooms, room.Snapshot())
+		}
+	}
+	return rooms, nil
+}
+
+// AddClient adds a client to a room, creating the room if it doesn't exist
+func (r *roomRepository) AddClient(roomID string, c *model.Client, mode repository.JoinMode) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	room, ok := r.rooms[roomID]
+	if ok && room.Config != nil && !room.Persistent() {
+		// The sweeper is about to close the room
+		return false, repository.ErrRoomExpired
+	}
+	if ok && room.Clients[c.ID] == nil {
+		if room.Locked && mode == repository.JoinAsMember {
+			return false, repository.ErrRoomLocked
+		}
+		if room.Config != nil && room.Config.Capacity > 0 && !c.Virtual && mode != repository.JoinAsHost &&
+			memberCount(room) >= room.Config.Capacity {
+			return false, repository.ErrRoomFull
+		}
+	}
+
+	// A client is a member of at most one room
+	if prev, ok := r.rooms[r.clients[c.ID]]; ok && prev.ID != roomID {
+		r.removeLocked(prev, c.ID)
+	}
+
+	if !ok {
+		room = model.NewRoom(roomID)
+		r.rooms[roomID] = room
+	}
+	if len(room.Clients) == 0 {
+		room.StartedAt = time.Now()
+	}
+	room.Clients[c.ID] = c
+	r.clients[c.ID] = roomID
+	if mode == repository.JoinAsHost || (room.HostID == "" && !c.Virtual) {
+		room.HostID = c.ID
+	}
+	return !ok, nil
+}
+
+// RemoveClient removes a client from its room and deletes the room once empty
+func (r *roomRepository) RemoveClient(clientID string) (string, bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	room, ok := r.rooms[r.clients[clientID]]
+	if !ok {
+		return "", false, repository.ErrNotFound
+	}
+	return room.ID, r.removeLocked(room, clientID), nil
+}
+
+// SetHost makes a member of a room its host
+func (r *roomRepository) SetHost(roomID, clientID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	room, ok := r.rooms[roomID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if _, ok := room.Clients[clientID]; !ok {
+		return repository.ErrNotFound
+	}
+	room.HostID = clientID
+	return nil
+}
+
+// SetLocked locks or unlocks a room
+func (r *roomRepository) SetLocked(roomID string, locked bool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	room, ok := r.rooms[roomID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	room.Locked = locked
+	return nil
+}
+
+// SetParent marks a room as a breakout room of parentID
+func (r *roomRepository) SetParent(roomID, parentID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	room, ok := r.rooms[roomID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	room.ParentID = parentID
+	return nil
+}
+
+// SetConfig makes a room persistent, or ad-hoc again with a nil cfg
+func (r *roomRepository) SetConfig(roomID string, cfg *model.RoomConfig) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	room, ok := r.rooms[roomID]
+	if !ok {
+		if cfg == nil {
+			return repository.ErrNotFound
+		}
+		room = model.NewRoom(roomID)
+		r.rooms[roomID] = room
+	}
+	room.Config = cfg
+	if cfg == nil && len(room.Clients) == 0 {
+		r.deleteLocked(room)
+	}
+	return nil
+}
+
+// nextHost picks the member that connected first; client IDs are xids, which
+// sort by creation time. Virtual clients cannot be host.
+func nextHost(room *model.Room) string {
+	next := ""
+	for id, c := range room.Clients {
+		if !c.Virtual && (next == "" || id < next) {
+			next = id
+		}
+	}
+	return next
+}
+
+// memberCount counts the members of a room that are not virtual
+func memberCount(room *model.Room) int {
+	n := 0
+	for _, c := range room.Clients {
+		if !c.Virtual {
+			n++
+		}
+	}
+	return n
+}
+
+// FindClient returns the room ID and client for a client ID
+func (r *roomRepository) FindClient(clientID string) (string, *model.Client, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	roomID, ok := r.clients[clientID]
+	if !ok {
+		return "", nil, repository.ErrNotFound
+	}
+	return roomID, r.rooms[roomID].Clients[clientID], nil
+}
+
+// GetClient returns a member of a room
+func (r *roomRepository) GetClient(roomID, clientID string) (*model.Client, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	room, ok := r.rooms[roomID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	client, ok := room.Clients[clientID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return client, nil
+}
+
+// Clients returns a snapshot of the members of a room
+func (r *roomRepository) Clients(roomID string) ([]*model.Client, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	room, ok := r.rooms[roomID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	clients := make([]*model.Client, 0, len(room.Clients))
+	for _, c := range room.Clients {
+		clients = append(clients, c)
+	}
+	return clients, nil
+}
+
+// Participants returns the members of a room that are not virtual
+func (r *roomRepository) Participants(roomID string) ([]model.Participant, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	room, ok := r.rooms[roomID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	participants := make([]model.Participant, 0, len(room.Clients))
+	for _, c := range room.Clients {
+		if !c.Virtual {
+			participants = append(participants, c.Participant())
+		}
+	}
+	return participants, nil
+}
+
+// UpdateParticipant has nothing to store; Participants reads the clients
+func (r *roomRepository) UpdateParticipant(c *model.Client) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if _, ok := r.clients[c.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// IsMember reports whether a client is a member of a room
+func (r *roomRepository) IsMember(clientID string) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, ok := r.clients[clientID]
+	return ok, nil
+}
+
+// removeLocked takes a client out of a room and deletes the room once empty,
+// unless it is persistent. It reports whether the room was deleted; callers
+// must hold the lock.
+func (r *roomRepository) removeLocked(room *model.Room, clientID string) bool {
+	delete(room.Clients, clientID)
+	delete(r.clients, clientID)
+	if len(room.Clients) > 0 {
+		if room.HostID == clientID {
+			room.HostID = nextHost(room)
+		}
+		return false
+	}
+	if room.Persistent() {
+		// An empty persistent room starts over like a new one
+		room.HostID = ""
+		room.Locked = false
+		room.StartedAt = time.Time{}
+		return false
+	}
+	r.deleteLocked(room)
+	return true
+}
+
+// putLocked stores a room and re-indexes its clients; callers must hold the lock
+func (r *roomRepository) putLocked(room *model.Room) {
+	if old, ok := r.rooms[room.ID]; ok {
+		r.unindexLocked(old)
+	}
+	r.rooms[room.ID] = room
+	for id := range room.Clients {
+		r.clients[id] = room.ID
+	}
+}
+
+// deleteLocked removes a room and its index entries; callers must hold the lock
+func (r *roomRepository) deleteLocked(room *model.Room) {
+	r.unindexLocked(room)
+	delete(r.rooms, room.ID)
+}
+
+// unindexLocked drops the index entries that still point at room
+func (r *roomRepository) unindexLocked(room *model.Room) {
+	for id := range room.Clients {
+		if r.clients[id] == room.ID {
+			delete(r.clients, id)
 		}
 	}
-	return nil, repository.ErrNotFound
 }
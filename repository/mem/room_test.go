@@ -0,0 +1,287 @@
+package mem
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+// checkIndex verifies that the client index and the members of every room
+// agree, and that every host is a member of its room
+func checkIndex(t *testing.T, r *roomRepository) {
+	t.Helper()
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	members := 0
+	for roomID, room := range r.rooms {
+		for id := range room.Clients {
+			if r.clients[id] != roomID {
+				t.Errorf("client %s is in room %s but indexed to %q", id, roomID, r.clients[id])
+			}
+		}
+		if room.HostID != "" && room.Clients[room.HostID] == nil {
+			t.Errorf("host %s of room %s is not a member", room.HostID, roomID)
+		}
+		if len(room.Clients) == 0 && !room.Persistent() {
+			t.Errorf("empty room %s was not deleted", roomID)
+		}
+		members += len(room.Clients)
+	}
+	for id, roomID := range r.clients {
+		room, ok := r.rooms[roomID]
+		if !ok || room.Clients[id] == nil {
+			t.Errorf("client %s is indexed to room %s but is not a member", id, roomID)
+		}
+	}
+	if members != len(r.clients) {
+		t.Errorf("%d members but %d index entries", members, len(r.clients))
+	}
+}
+
+// TestConcurrentMembership joins, moves, leaves and hands over the host role
+// from many goroutines while others read, then checks each client ended up
+// where its last operation put it
+func TestConcurrentMembership(t *testing.T) {
+	const (
+		workers   = 8
+		perWorker = 8
+		rooms     = 6
+		ops       = 2000
+	)
+	r := NewRoomRepository().(*roomRepository)
+	r.SetConfig("persistent", &model.RoomConfig{})
+
+	roomIDs := []string{"persistent"}
+	for i := 1; i < rooms; i++ {
+		roomIDs = append(roomIDs, fmt.Sprintf("room-%d", i))
+	}
+
+	var (
+		wg       sync.WaitGroup
+		expected sync.Map // client ID -> room ID, "" once it left
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			clients := make([]*model.Client, perWorker)
+			where := make(map[string]string)
+			for i := range clients {
+				clients[i] = model.NewClient("user")
+			}
+
+			for i := 0; i < ops; i++ {
+				c := clients[rnd.Intn(len(clients))]
+				roomID := roomIDs[rnd.Intn(len(roomIDs))]
+				switch rnd.Intn(6) {
+				case 0, 1:
+					// Joining another room moves the client
+					if _, err := r.AddClient(roomID, c, repository.JoinAsMember); err != nil {
+						t.Errorf("join %s: %v", roomID, err)
+						return
+					}
+					where[c.ID] = roomID
+				case 2:
+					if _, _, err := r.RemoveClient(c.ID); err == nil {
+						where[c.ID] = ""
+					} else if where[c.ID] != "" {
+						t.Errorf("leave %s: %v", where[c.ID], err)
+						return
+					}
+				case 3:
+					if where[c.ID] != "" {
+						// Fails when the room is deleted meanwhile, which cannot happen to a member
+						if err := r.SetHost(where[c.ID], c.ID); err != nil {
+							t.Errorf("transfer host of %s: %v", where[c.ID], err)
+							return
+						}
+					}
+				case 4:
+					if room, err := r.GetByClientID(c.ID); err == nil && room.ID != where[c.ID] {
+						t.Errorf("client %s found in %s, want %q", c.ID, room.ID, where[c.ID])
+						return
+					}
+				case 5:
+					roomID, _, err := r.FindClient(c.ID)
+					if (err == nil) != (where[c.ID] != "") || roomID != where[c.ID] {
+						t.Errorf("client %s found in %q (%v), want %q", c.ID, roomID, err, where[c.ID])
+						return
+					}
+				}
+			}
+			for id, roomID := range where {
+				expected.Store(id, roomID)
+			}
+		}(int64(w))
+	}
+
+	// Readers walking the rooms while they change
+	stop := make(chan struct{})
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				rooms, _ := r.List()
+				for _, room := range rooms {
+					r.Clients(room.ID)
+				}
+				runtime.Gosched()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(stop)
+	readers.Wait()
+
+	checkIndex(t, r)
+	expected.Range(func(key, value interface{}) bool {
+		clientID, roomID := key.(string), value.(string)
+		got, _, err := r.FindClient(clientID)
+		if roomID == "" {
+			if err != repository.ErrNotFound {
+				t.Errorf("client %s left but is in %s", clientID, got)
+			}
+		} else if got != roomID {
+			t.Errorf("client %s is in %q, want %s", clientID, got, roomID)
+		}
+		return true
+	})
+}
+
+// TestConcurrentJoinSameRoom races many first joins of a single room; exactly
+// one of them creates it and one member becomes host
+func TestConcurrentJoinSameRoom(t *testing.T) {
+	r := NewRoomRepository().(*roomRepository)
+
+	const joins = 64
+	var (
+		wg      sync.WaitGroup
+		created sync.Map
+	)
+	for i := 0; i < joins; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ok, err := r.AddClient("room", model.NewClient("user"), repository.JoinAsMember)
+			if err != nil {
+				t.Errorf("join: %v", err)
+			}
+			created.Store(i, ok)
+		}(i)
+	}
+	wg.Wait()
+
+	n := 0
+	created.Range(func(_, value interface{}) bool {
+		if value.(bool) {
+			n++
+		}
+		return true
+	})
+	if n != 1 {
+		t.Errorf("room created %d times, want 1", n)
+	}
+	room, err := r.Get("room")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(room.Clients) != joins {
+		t.Errorf("%d members, want %d", len(room.Clients), joins)
+	}
+	if room.Clients[room.HostID] == nil {
+		t.Errorf("host %q is not a member", room.HostID)
+	}
+	checkIndex(t, r)
+}
+
+// TestParticipants checks that participants leave out virtual clients and
+// reflect profile changes
+func TestParticipants(t *testing.T) {
+	r := NewRoomRepository()
+	member, viewer := model.NewClient("alice"), model.NewVirtualClient("whep")
+	r.AddClient("room", member, repository.JoinAsMember)
+	r.AddClient("room", viewer, repository.JoinAsMember)
+
+	member.SetProfile(model.Profile{Name: "Alice"})
+	if err := r.UpdateParticipant(member); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	participants, err := r.Participants("room")
+	if err != nil {
+		t.Fatalf("participants: %v", err)
+	}
+	if len(participants) != 1 || participants[0].ClientID != member.ID || participants[0].Name != "Alice" {
+		t.Errorf("got %+v, want only %s named Alice", participants, member.ID)
+	}
+
+	if err := r.UpdateParticipant(model.NewClient("bob")); err != repository.ErrNotFound {
+		t.Errorf("update of a non-member: got %v, want ErrNotFound", err)
+	}
+	if _, err := r.Participants("other"); err != repository.ErrNotFound {
+		t.Errorf("participants of a missing room: got %v, want ErrNotFound", err)
+	}
+}
+
+// benchmarkRooms fills a repository with rooms of four members each and
+// returns the IDs of all members
+func benchmarkRooms(b *testing.B, rooms int) (repository.Room, []string) {
+	r := NewRoomRepository()
+	clientIDs := make([]string, 0, rooms*4)
+	for i := 0; i < rooms; i++ {
+		roomID := fmt.Sprintf("room-%d", i)
+		for j := 0; j < 4; j++ {
+			c := model.NewClient("user")
+			if _, err := r.AddClient(roomID, c, repository.JoinAsMember); err != nil {
+				b.Fatalf("join: %v", err)
+			}
+			clientIDs = append(clientIDs, c.ID)
+		}
+	}
+	return r, clientIDs
+}
+
+// The time per lookup should not grow with the number of rooms
+var benchmarkSizes = []int{10, 1000, 10000}
+
+func BenchmarkGetByClientID(b *testing.B) {
+	for _, rooms := range benchmarkSizes {
+		b.Run(fmt.Sprintf("rooms=%d", rooms), func(b *testing.B) {
+			r, clientIDs := benchmarkRooms(b, rooms)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := r.GetByClientID(clientIDs[i%len(clientIDs)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFindClient(b *testing.B) {
+	for _, rooms := range benchmarkSizes {
+		b.Run(fmt.Sprintf("rooms=%d", rooms), func(b *testing.B) {
+			r, clientIDs := benchmarkRooms(b, rooms)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := r.FindClient(clientIDs[i%len(clientIDs)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gosignaling/model"
+	"gosignaling/repository"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// appendScript appends a message and trims the history to its size limit in
+// one step, so pods appending concurrently cannot leave orphaned messages.
+// KEYS: ids list, messages hash. ARGV: message ID, message JSON, size, ttl ms.
+var appendScript = goredis.NewScript(`
+redis.call('RPUSH', KEYS[1], ARGV[1])
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+while redis.call('LLEN', KEYS[1]) > tonumber(ARGV[3]) do
+	local id = redis.call('LPOP', KEYS[1])
+	redis.call('HDEL', KEYS[2], id)
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+redis.call('PEXPIRE', KEYS[2], ARGV[4])
+return 1
+`)
+
+// updateScript replaces a message only if it is still in the history.
+// KEYS: messages hash. ARGV: message ID, message JSON.
+var updateScript = goredis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+return 1
+`)
+
+type chatRepository struct {
+	rdb  *goredis.Client
+	ctx  context.Context
+	size int
+	ttl  time.Duration
+}
+
+// NewChatRepository creates a chat history shared by all pods through Redis.
+// It keeps size messages per room; a room's history expires ttl after its
+// last message.
+func NewChatRepository(rdb *goredis.Client, size int, ttl time.Duration) repository.Chat {
+	return &chatRepository{
+		rdb:  rdb,
+		ctx:  context.Background(),
+		size: size,
+		ttl:  ttl,
+	}
+}
+
+// Append adds a message and drops the oldest ones beyond the size limit
+func (r *chatRepository) Append(roomID string, msg *model.ChatMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return appendScript.Run(r.ctx, r.rdb, []string{idsKey(roomID), messagesKey(roomID)},
+		msg.ID, data, r.size, r.ttl.Milliseconds()).Err()
+}
+
+// Recent returns up to n of the latest messages, oldest first
+func (r *chatRepository) Recent(roomID string, n int) ([]*model.ChatMessage, error) {
+	ids, err := r.rdb.LRange(r.ctx, idsKey(roomID), int64(-n), -1).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	values, err := r.rdb.HMGet(r.ctx, messagesKey(roomID), ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*model.ChatMessage, 0, len(values))
+	for _, v := range values {
+		// Messages deleted between the two reads come back as nil
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var msg model.ChatMessage
+		if err := json.Unmarshal([]byte(s), &msg); err != nil {
+			continue
+		}
+		messages = append(messages, &msg)
+	}
+	return messages, nil
+}
+
+// Get returns a message from the history of a room
+func (r *chatRepository) Get(roomID, messageID string) (*model.ChatMessage, error) {
+	data, err := r.rdb.HGet(r.ctx, messagesKey(roomID), messageID).Bytes()
+	if err == goredis.Nil {
+		return nil, repository.ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var msg model.ChatMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// Update replaces a message that is still in the history
+func (r *chatRepository) Update(roomID string, msg *model.ChatMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	updated, err := updateScript.Run(r.ctx, r.rdb, []string{messagesKey(roomID)}, msg.ID, data).Int()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return repository.ErrMessageNotFound
+	}
+	return nil
+}
+
+// Delete removes a message from the history
+func (r *chatRepository) Delete(roomID, messageID string) error {
+	var deleted *goredis.IntCmd
+	_, err := r.rdb.TxPipelined(r.ctx, func(pipe goredis.Pipeliner) error {
+		pipe.LRem(r.ctx, idsKey(roomID), 0, messageID)
+		deleted = pipe.HDel(r.ctx, messagesKey(roomID), messageID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if deleted.Val() == 0 {
+		return repository.ErrMessageNotFound
+	}
+	return nil
+}
+
+// Forget keeps the history, which members on other pods may still need; it
+// expires on its own
+func (r *chatRepository) Forget(roomID string) error {
+	return nil
+}
+
+func idsKey(roomID string) string {
+	return "chat:" + roomID + ":ids"
+}
+
+func messagesKey(roomID string) string {
+	return "chat:" + roomID + ":messages"
+}
@@ -0,0 +1,565 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"gosignaling/model"
+	"gosignaling/repository"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// nodesKey is the sorted set of pods by the time of their last heartbeat
+const nodesKey = "cluster:nodes"
+
+const (
+	// nodeHeartbeat is how often a pod tells the others it is alive
+	nodeHeartbeat = 5 * time.Second
+	// nodeTimeout drops the members of a pod that stopped sending heartbeats
+	nodeTimeout = 4 * nodeHeartbeat
+)
+
+// memberScripts is the part shared by the membership scripts. KEYS and ARGV
+// are listed for each script.
+const memberScripts = `
+local now, timeout = tonumber(ARGV[1]), tonumber(ARGV[2])
+
+-- members returns the entries of a room by client ID and drops those of pods
+-- that stopped sending heartbeats
+local function members(key, nodes)
+	local live = {}
+	local entries = redis.call('HGETALL', key)
+	for i = 1, #entries, 2 do
+		local m = cjson.decode(entries[i + 1])
+		local seen = redis.call('ZSCORE', nodes, m.node)
+		if seen and now - tonumber(seen) <= timeout then
+			live[entries[i]] = m
+		else
+			redis.call('HDEL', key, entries[i])
+		end
+	end
+	return live
+end
+
+-- ensureHost passes the host role on if its holder is gone, to the member
+-- that connected first; client IDs sort by creation time
+local function ensureHost(state, live)
+	local host = redis.call('HGET', state, 'host')
+	if host and live[host] and not live[host].virtual then
+		return
+	end
+	local first
+	for id, m in pairs(live) do
+		if not m.virtual and (not first or id < first) then
+			first = id
+		end
+	end
+	if first then
+		redis.call('HSET', state, 'host', first)
+	else
+		redis.call('HDEL', state, 'host')
+	end
+end
+
+-- settle drops the members of stopped pods, deletes the room once empty and
+-- passes the host role on; it returns the remaining members
+local function settle(key, state, nodes)
+	local live = members(key, nodes)
+	if next(live) == nil then
+		redis.call('DEL', key, state)
+	else
+		ensureHost(state, live)
+	end
+	return live
+end
+`
+
+// Results of joinScript
+const (
+	joinOK     = 1
+	joinLocked = 2
+	joinFull   = 3
+)
+
+// joinScript moves a client into a room unless the room is locked for it or
+// has no room left for it.
+// KEYS: members, state, previous members, previous state, nodes.
+// ARGV: now ms, timeout ms, client ID, member entry, join as host ("1" or
+// "0"), previous room ("1" or "0"), check the lock ("1" or "0"), capacity
+// (0 is unlimited).
+var joinScript = goredis.NewScript(memberScripts + `
+local id, entry = ARGV[3], ARGV[4]
+local live = members(KEYS[1], KEYS[5])
+if next(live) == nil then
+	-- An empty room starts over
+	redis.call('DEL', KEYS[2])
+elseif not live[id] and ARGV[7] == '1' and redis.call('HGET', KEYS[2], 'locked') == '1' then
+	return 2
+end
+local capacity = tonumber(ARGV[8])
+if capacity > 0 and not live[id] then
+	local count = 0
+	for _, m in pairs(live) do
+		if not m.virtual then
+			count = count + 1
+		end
+	end
+	if count >= capacity then
+		return 3
+	end
+end
+if ARGV[6] == '1' then
+	redis.call('HDEL', KEYS[3], id)
+	settle(KEYS[3], KEYS[4], KEYS[5])
+end
+redis.call('HSET', KEYS[1], id, entry)
+live[id] = cjson.decode(entry)
+if ARGV[5] == '1' then
+	redis.call('HSET', KEYS[2], 'host', id)
+else
+	ensureHost(KEYS[2], live)
+end
+return 1
+`)
+
+// leaveScript removes a client from a room.
+// KEYS: members, state, nodes. ARGV: now ms, timeout ms, client ID.
+var leaveScript = goredis.NewScript(memberScripts + `
+redis.call('HDEL', KEYS[1], ARGV[3])
+settle(KEYS[1], KEYS[2], KEYS[3])
+return 1
+`)
+
+// membersScript returns the member entries of a room.
+// KEYS: members, state, nodes. ARGV: now ms, timeout ms.
+var membersScript = goredis.NewScript(memberScripts + `
+settle(KEYS[1], KEYS[2], KEYS[3])
+return redis.call('HVALS', KEYS[1])
+`)
+
+// setHostScript makes a member that is not virtual host of its room.
+// KEYS: members, state, nodes. ARGV: now ms, timeout ms, client ID.
+var setHostScript = goredis.NewScript(memberScripts + `
+local m = settle(KEYS[1], KEYS[2], KEYS[3])[ARGV[3]]
+if not m or m.virtual then
+	return 0
+end
+redis.call('HSET', KEYS[2], 'host', ARGV[3])
+return 1
+`)
+
+// setStateScript sets a field of the state of a room that has members.
+// KEYS: members, state. ARGV: field, value.
+var setStateScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+return 1
+`)
+
+// updateMemberScript replaces the entry of a client that is still a member.
+// KEYS: members. ARGV: client ID, member entry.
+var updateMemberScript = goredis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+return 1
+`)
+
+// memberEntry is a member of a room as stored in Redis
+type memberEntry struct {
+	Node        string            `json:"node"`
+	Virtual     bool              `json:"virtual,omitempty"`
+	Participant model.Participant `json:"participant"`
+}
+
+// clusterRoomRepository shares the members of rooms, their host, their lock
+// and their parent room between pods. The local repository holds the clients connected to this pod; Redis
+// holds the members of every pod, which Participants returns, and the state
+// of each room, which overrides the local copy in the rooms returned.
+type clusterRoomRepository struct {
+	repository.Room
+	rdb  *goredis.Client
+	ctx  context.Context
+	node string
+}
+
+// NewClusterRoomRepository creates a room repository that shares membership
+// with the other pods through Redis. node identifies this pod; its members
+// are dropped if it stops sending heartbeats.
+func NewClusterRoomRepository(rdb *goredis.Client, local repository.Room, node string) repository.Room {
+	r := &clusterRoomRepository{
+		Room: local,
+		rdb:  rdb,
+		ctx:  context.Background(),
+		node: node,
+	}
+	err := r.heartbeat()
+	if err != nil {
+		log.Printf("Failed to send cluster heartbeat: %v", err)
+	}
+	go func(alive bool) {
+		for range time.Tick(nodeHeartbeat) {
+			err := r.heartbeat()
+			if err != nil {
+				log.Printf("Failed to send cluster heartbeat: %v", err)
+			} else if !alive {
+				// Other pods may have dropped the members of this pod meanwhile
+				r.restore()
+			}
+			alive = err == nil
+		}
+	}(err == nil)
+	return r
+}
+
+// Get returns a room with its shared state. A room whose members are all on
+// other pods is returned without clients.
+func (r *clusterRoomRepository) Get(roomID string) (*model.Room, error) {
+	room, err := r.Room.Get(roomID)
+	if err == repository.ErrNotFound {
+		if n, err := r.rdb.Exists(r.ctx, membersKey(roomID)).Result(); err == nil && n > 0 {
+			room, err = model.NewRoom(roomID), nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.overlay(room)
+	return room, nil
+}
+
+// GetByClientID returns the room of a client on this pod with its shared state
+func (r *clusterRoomRepository) GetByClientID(clientID string) (*model.Room, error) {
+	room, err := r.Room.GetByClientID(clientID)
+	if err != nil {
+		return nil, err
+	}
+	r.overlay(room)
+	return room, nil
+}
+
+// List returns the rooms with members on this pod with their shared state
+func (r *clusterRoomRepository) List() ([]*model.Room, error) {
+	rooms, err := r.Room.List()
+	if err != nil {
+		return nil, err
+	}
+	r.overlay(rooms...)
+	return rooms, nil
+}
+
+// AddClient adds a client to a room in Redis and on this pod. The lock and
+// the capacity are checked in Redis; if Redis fails, the error is logged and the client joins
+// on this pod only, as the local copy allows.
+func (r *clusterRoomRepository) AddClient(roomID string, c *model.Client, mode repository.JoinMode) (bool, error) {
+	prevRoomID, _, err := r.Room.FindClient(c.ID)
+	if err != nil {
+		prevRoomID = ""
+	}
+	// Capacity counts the members on all pods
+	capacity := 0
+	if room, err := r.Room.Get(roomID); err == nil && room.Config != nil && !c.Virtual && mode != repository.JoinAsHost {
+		capacity = room.Config.Capacity
+	}
+	switch result, err := r.join(roomID, prevRoomID, c, mode, capacity); {
+	case err != nil:
+		logShareError(err, c.ID, roomID)
+	case result == joinLocked:
+		return false, repository.ErrRoomLocked
+	case result == joinFull:
+		return false, repository.ErrRoomFull
+	case mode == repository.JoinAsMember:
+		// The local copy of the lock may be stale
+		mode = repository.JoinAdmitted
+	}
+
+	created, err := r.Room.AddClient(roomID, c, mode)
+	if err != nil {
+		// The client stays where it was
+		if prevRoomID != "" {
+			_, err := r.join(prevRoomID, roomID, c, repository.JoinAdmitted, 0)
+			logShareError(err, c.ID, prevRoomID)
+		} else {
+			logShareError(r.leave(roomID, c.ID), c.ID, roomID)
+		}
+		return false, err
+	}
+	return created, nil
+}
+
+// RemoveClient removes a client from its room on this pod and in Redis
+func (r *clusterRoomRepository) RemoveClient(clientID string) (string, bool, error) {
+	roomID, deleted, err := r.Room.RemoveClient(clientID)
+	if err != nil {
+		return roomID, deleted, err
+	}
+	logShareError(r.leave(roomID, clientID), clientID, roomID)
+	return roomID, deleted, nil
+}
+
+// SetHost makes a member of a room on any pod its host
+func (r *clusterRoomRepository) SetHost(roomID, clientID string) error {
+	keys := []string{membersKey(roomID), stateKey(roomID), nodesKey}
+	ok, err := setHostScript.Run(r.ctx, r.rdb, keys, r.now(), nodeTimeout.Milliseconds(), clientID).Bool()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return repository.ErrNotFound
+	}
+	// The local copy is only used while Redis cannot be reached
+	if err := r.Room.SetHost(roomID, clientID); err != nil && err != repository.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// SetLocked locks or unlocks a room on every pod
+func (r *clusterRoomRepository) SetLocked(roomID string, locked bool) error {
+	if err := r.setState(roomID, "locked", flag(locked)); err != nil {
+		return err
+	}
+	if err := r.Room.SetLocked(roomID, locked); err != nil && err != repository.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// SetParent marks a room as a breakout room of parentID on every pod
+func (r *clusterRoomRepository) SetParent(roomID, parentID string) error {
+	if err := r.setState(roomID, "parent", parentID); err != nil {
+		return err
+	}
+	if err := r.Room.SetParent(roomID, parentID); err != nil && err != repository.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// Participants returns the members of a room on every pod. If Redis fails,
+// it returns those on this pod.
+func (r *clusterRoomRepository) Participants(roomID string) ([]model.Participant, error) {
+	keys := []string{membersKey(roomID), stateKey(roomID), nodesKey}
+	entries, err := membersScript.Run(r.ctx, r.rdb, keys, r.now(), nodeTimeout.Milliseconds()).StringSlice()
+	if err != nil {
+		log.Printf("Failed to load members of room %s: %v", roomID, err)
+		return r.Room.Participants(roomID)
+	}
+	if len(entries) == 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	participants := make([]model.Participant, 0, len(entries))
+	for _, data := range entries {
+		var m memberEntry
+		if err := json.Unmarshal([]byte(data), &m); err != nil || m.Virtual {
+			continue
+		}
+		participants = append(participants, m.Participant)
+	}
+	return participants, nil
+}
+
+// IsMember reports whether a client is a member of a room on this pod or on
+// another pod that still sends heartbeats
+func (r *clusterRoomRepository) IsMember(clientID string) (bool, error) {
+	if ok, err := r.Room.IsMember(clientID); ok || err != nil {
+		return ok, err
+	}
+	nodes, err := LiveNodes(r.rdb)
+	if err != nil {
+		return false, err
+	}
+	cmds := make([]*goredis.BoolCmd, len(nodes))
+	_, err = r.rdb.Pipelined(r.ctx, func(pipe goredis.Pipeliner) error {
+		for i, node := range nodes {
+			cmds[i] = pipe.HExists(r.ctx, nodeClientsKey(node), clientID)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, cmd := range cmds {
+		if cmd.Val() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UpdateParticipant stores the profile and state of a member in Redis
+func (r *clusterRoomRepository) UpdateParticipant(c *model.Client) error {
+	roomID, _, err := r.Room.FindClient(c.ID)
+	if err != nil {
+		return err
+	}
+	entry, err := r.entry(c)
+	if err != nil {
+		return err
+	}
+	return updateMemberScript.Run(r.ctx, r.rdb, []string{membersKey(roomID)}, c.ID, entry).Err()
+}
+
+// setState sets a field of the shared state of a room with members
+func (r *clusterRoomRepository) setState(roomID, field, value string) error {
+	ok, err := setStateScript.Run(r.ctx, r.rdb, []string{membersKey(roomID), stateKey(roomID)}, field, value).Bool()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// join moves a client into roomID from prevRoomID, if not empty, in Redis
+// and returns the result of joinScript. A capacity of 0 is unlimited.
+func (r *clusterRoomRepository) join(roomID, prevRoomID string, c *model.Client, mode repository.JoinMode, capacity int) (int, error) {
+	entry, err := r.entry(c)
+	if err != nil {
+		return 0, err
+	}
+	keys := []string{membersKey(roomID), stateKey(roomID), membersKey(prevRoomID), stateKey(prevRoomID), nodesKey}
+	result, err := joinScript.Run(r.ctx, r.rdb, keys, r.now(), nodeTimeout.Milliseconds(), c.ID, entry,
+		flag(mode == repository.JoinAsHost), flag(prevRoomID != "" && prevRoomID != roomID),
+		flag(mode == repository.JoinAsMember), capacity).Int()
+	if err != nil || result != joinOK {
+		return result, err
+	}
+	_, err = r.rdb.Pipelined(r.ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(r.ctx, nodeClientsKey(r.node), c.ID, roomID)
+		pipe.Expire(r.ctx, nodeClientsKey(r.node), nodeTimeout)
+		return nil
+	})
+	return result, err
+}
+
+// leave removes a client from a room in Redis
+func (r *clusterRoomRepository) leave(roomID, clientID string) error {
+	keys := []string{membersKey(roomID), stateKey(roomID), nodesKey}
+	if err := leaveScript.Run(r.ctx, r.rdb, keys, r.now(), nodeTimeout.Milliseconds(), clientID).Err(); err != nil {
+		return err
+	}
+	return r.rdb.HDel(r.ctx, nodeClientsKey(r.node), clientID).Err()
+}
+
+// overlay replaces the state of rooms with the shared state. Where Redis
+// has none, e.g. because it failed, the local state is kept.
+func (r *clusterRoomRepository) overlay(rooms ...*model.Room) {
+	cmds := make([]*goredis.SliceCmd, len(rooms))
+	_, err := r.rdb.Pipelined(r.ctx, func(pipe goredis.Pipeliner) error {
+		for i, room := range rooms {
+			cmds[i] = pipe.HMGet(r.ctx, stateKey(room.ID), "host", "locked", "parent")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to load shared state of rooms: %v", err)
+		return
+	}
+	for i, room := range rooms {
+		values := cmds[i].Val()
+		if host, ok := values[0].(string); ok {
+			room.HostID = host
+		}
+		if locked, ok := values[1].(string); ok {
+			room.Locked = locked == "1"
+		}
+		if parent, ok := values[2].(string); ok {
+			room.ParentID = parent
+		}
+	}
+}
+
+// entry encodes a client as a member of a room on this pod
+func (r *clusterRoomRepository) entry(c *model.Client) ([]byte, error) {
+	return json.Marshal(memberEntry{
+		Node:        r.node,
+		Virtual:     c.Virtual,
+		Participant: c.Participant(),
+	})
+}
+
+// heartbeat keeps the members of this pod alive and forgets pods that have
+// been gone for long. The index of its members expires with the heartbeats.
+func (r *clusterRoomRepository) heartbeat() error {
+	now := r.now()
+	_, err := r.rdb.Pipelined(r.ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZAdd(r.ctx, nodesKey, &goredis.Z{Score: float64(now), Member: r.node})
+		pipe.Expire(r.ctx, nodeClientsKey(r.node), nodeTimeout)
+		pipe.ZRemRangeByScore(r.ctx, nodesKey, "-inf", fmtScore(now-time.Hour.Milliseconds()))
+		return nil
+	})
+	return err
+}
+
+// restore shares the members of this pod again
+func (r *clusterRoomRepository) restore() {
+	rooms, err := r.Room.List()
+	if err != nil {
+		return
+	}
+	for _, room := range rooms {
+		for _, c := range room.Clients {
+			mode := repository.JoinAdmitted
+			if c.ID == room.HostID {
+				mode = repository.JoinAsHost
+			}
+			_, err := r.join(room.ID, "", c, mode, 0)
+			logShareError(err, c.ID, room.ID)
+		}
+	}
+}
+
+// LiveNodes returns the pods that sent a cluster heartbeat within the node
+// timeout
+func LiveNodes(rdb *goredis.Client) ([]string, error) {
+	return rdb.ZRangeByScore(context.Background(), nodesKey, &goredis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().UnixMilli()-nodeTimeout.Milliseconds(), 10),
+		Max: "+inf",
+	}).Result()
+}
+
+// now returns the time the scripts compare heartbeats with, in milliseconds
+func (r *clusterRoomRepository) now() int64 {
+	return time.Now().UnixMilli()
+}
+
+func logShareError(err error, clientID, roomID string) {
+	if err != nil {
+		log.Printf("Failed to share membership of %s in room %s: %v", clientID, roomID, err)
+	}
+}
+
+func flag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func fmtScore(score int64) string {
+	return "(" + strconv.FormatInt(score, 10)
+}
+
+func membersKey(roomID string) string {
+	return "room:" + roomID + ":members"
+}
+
+// nodeClientsKey is the hash of the members connected to a pod, with the
+// room of each, which IsMember looks clients up in
+func nodeClientsKey(node string) string {
+	return "cluster:node:" + node + ":clients"
+}
+
+func stateKey(roomID string) string {
+	return "room:" + roomID + ":state"
+}
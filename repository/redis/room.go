@@ -0,0 +1,157 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"gosignaling/model"
+	"gosignaling/repository"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// roomConfigsKey is the hash of persistent room configurations by room ID
+const roomConfigsKey = "rooms:config"
+
+// roomRepository keeps the members of rooms in the local repository, which
+// only knows the clients connected to this pod, and stores the configuration
+// of persistent rooms in Redis so that every pod and restart sees them
+type roomRepository struct {
+	repository.Room
+	rdb     *goredis.Client
+	ctx     context.Context
+	refresh *repository.ConfigRefresh
+}
+
+// NewRoomRepository creates a room repository that persists room
+// configurations in Redis and keeps members in local
+func NewRoomRepository(rdb *goredis.Client, local repository.Room) repository.Room {
+	r := &roomRepository{
+		Room:    local,
+		rdb:     rdb,
+		ctx:     context.Background(),
+		refresh: repository.NewConfigRefresh(repository.ConfigRefreshInterval),
+	}
+
+	configs, err := r.loadAll()
+	if err != nil {
+		log.Printf("Failed to load persistent rooms: %v", err)
+	}
+	for roomID, cfg := range configs {
+		local.SetConfig(roomID, cfg)
+	}
+	if len(configs) > 0 {
+		log.Printf("🏠 Loaded %d persistent rooms", len(configs))
+	}
+	return r
+}
+
+// Get retrieves a room by ID with its current configuration
+func (r *roomRepository) Get(roomID string) (*model.Room, error) {
+	r.sync(roomID)
+	return r.Room.Get(roomID)
+}
+
+// ListPersistent returns the persistent rooms of all pods, with the members
+// on this pod
+func (r *roomRepository) ListPersistent() ([]*model.Room, error) {
+	configs, err := r.loadAll()
+	if err != nil {
+		return nil, err
+	}
+	rooms := make([]*model.Room, 0, len(configs))
+	for roomID, cfg := range configs {
+		room, err := r.Room.Get(roomID)
+		if err != nil {
+			room = model.NewRoom(roomID)
+		}
+		room.Config = cfg
+		rooms = append(rooms, room)
+	}
+	return rooms, nil
+}
+
+// AddClient adds a client to a room after picking up configuration changes
+// made on other pods
+func (r *roomRepository) AddClient(roomID string, c *model.Client, mode repository.JoinMode) (bool, error) {
+	r.sync(roomID)
+	return r.Room.AddClient(roomID, c, mode)
+}
+
+// SetConfig stores or removes the configuration of a room
+func (r *roomRepository) SetConfig(roomID string, cfg *model.RoomConfig) error {
+	if cfg == nil {
+		if err := r.rdb.HDel(r.ctx, roomConfigsKey, roomID).Err(); err != nil {
+			return err
+		}
+	} else {
+		data, err := json.Marshal(cfg)
+		if err != nil {
+			return err
+		}
+		if err := r.rdb.HSet(r.ctx, roomConfigsKey, roomID, data).Err(); err != nil {
+			return err
+		}
+	}
+	return r.Room.SetConfig(roomID, cfg)
+}
+
+// sync copies the stored configuration of a room to the local repository,
+// at most once per repository.ConfigRefreshInterval. Redis errors are logged
+// and the local copy is used.
+func (r *roomRepository) sync(roomID string) {
+	if !r.refresh.Due(roomID, time.Now()) {
+		return
+	}
+	var cfg *model.RoomConfig
+	data, err := r.rdb.HGet(r.ctx, roomConfigsKey, roomID).Bytes()
+	switch {
+	case err == goredis.Nil:
+	case err != nil:
+		log.Printf("Failed to load configuration of room %s: %v", roomID, err)
+		r.refresh.Forget(roomID)
+		return
+	default:
+		cfg = &model.RoomConfig{}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			log.Printf("Invalid configuration of room %s: %v", roomID, err)
+			return
+		}
+	}
+
+	room, err := r.Room.Get(roomID)
+	if err == repository.ErrNotFound && cfg == nil {
+		return
+	}
+	if err == nil && room.Config.Equal(cfg) {
+		return
+	}
+	r.Room.SetConfig(roomID, cfg)
+}
+
+// loadAll returns the stored configurations that have not expired and drops
+// the expired ones
+func (r *roomRepository) loadAll() (map[string]*model.RoomConfig, error) {
+	values, err := r.rdb.HGetAll(r.ctx, roomConfigsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	configs := make(map[string]*model.RoomConfig, len(values))
+	for roomID, data := range values {
+		var cfg model.RoomConfig
+		if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+			log.Printf("Invalid configuration of room %s: %v", roomID, err)
+			continue
+		}
+		if cfg.Expired(now) {
+			r.rdb.HDel(r.ctx, roomConfigsKey, roomID)
+			continue
+		}
+		configs[roomID] = &cfg
+	}
+	return configs, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"sync"
+	"time"
+)
+
+// ConfigRefreshInterval is how long a pod uses its copy of a room's stored
+// configuration before reading it again, so that joins and lookups do not
+// each cost a round trip to the shared store
+const ConfigRefreshInterval = 5 * time.Second
+
+// ConfigRefresh tracks when the stored configuration of each room was last
+// read. It is safe for concurrent use.
+type ConfigRefresh struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	read     map[string]time.Time
+}
+
+// NewConfigRefresh creates a ConfigRefresh that reads configurations again
+// after interval
+func NewConfigRefresh(interval time.Duration) *ConfigRefresh {
+	return &ConfigRefresh{
+		interval: interval,
+		read:     make(map[string]time.Time),
+	}
+}
+
+// Due reports whether the configuration of a room should be read again. It
+// counts the room as read; call Forget if reading fails.
+func (f *ConfigRefresh) Due(roomID string, now time.Time) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if last, ok := f.read[roomID]; ok && now.Sub(last) < f.interval {
+		return false
+	}
+	// Rooms that were not looked up for a while need no entry
+	if len(f.read) >= 1024 {
+		for id, last := range f.read {
+			if now.Sub(last) >= f.interval {
+				delete(f.read, id)
+			}
+		}
+	}
+	f.read[roomID] = now
+	return true
+}
+
+// Forget makes the next Due of a room report true
+func (f *ConfigRefresh) Forget(roomID string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.read, roomID)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigRefresh(t *testing.T) {
+	f := NewConfigRefresh(time.Second)
+	now := time.Now()
+
+	if !f.Due("room", now) {
+		t.Error("first lookup is not due")
+	}
+	if f.Due("room", now.Add(time.Second/2)) {
+		t.Error("lookup within the interval is due")
+	}
+	if !f.Due("other", now) {
+		t.Error("lookup of another room is not due")
+	}
+	if !f.Due("room", now.Add(time.Second)) {
+		t.Error("lookup after the interval is not due")
+	}
+	f.Forget("room")
+	if !f.Due("room", now.Add(time.Second)) {
+		t.Error("forgotten room is not due")
+	}
+}
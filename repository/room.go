@@ -6,15 +6,78 @@ import (
 	"gosignaling/model"
 )
 
-// Room defines the interface for room repository
+// Room defines the interface for room repository.
+// Rooms returned by Get and GetByClientID are snapshots; membership changes
+// must go through AddClient and RemoveClient so they are applied atomically.
+//
+// Client lookups (GetByClientID, FindClient, RemoveClient) sit on the hot path
+// of every offer, answer and ICE candidate, so implementations must resolve a
+// client's room through an index in O(1) rather than by scanning rooms.
 type Room interface {
 	Get(roomID string) (*model.Room, error)
 	Create(r *model.Room) (*model.Room, error)
 	Update(r *model.Room) (*model.Room, error)
 	Delete(roomID string) error
 	GetByClientID(clientID string) (*model.Room, error)
+	// List returns snapshots of all rooms
+	List() ([]*model.Room, error)
+	// ListPersistent returns snapshots of the persistent rooms, including
+	// those without members
+	ListPersistent() ([]*model.Room, error)
+
+	// AddClient adds a client to a room, creating the room if it doesn't exist.
+	// The first member that is not virtual becomes host. A locked room
+	// rejects JoinAsMember with ErrRoomLocked, and a persistent room at
+	// capacity rejects clients that are neither virtual nor host with
+	// ErrRoomFull. A persistent room past its expiry rejects everyone with
+	// ErrRoomExpired until it is closed.
+	AddClient(roomID string, c *model.Client, mode JoinMode) (created bool, err error)
+	// RemoveClient removes a client from its room and deletes the room once
+	// empty, unless it is persistent. When the host leaves, the
+	// longest-connected member takes over.
+	RemoveClient(clientID string) (roomID string, deleted bool, err error)
+	// SetHost makes a member of a room its host
+	SetHost(roomID, clientID string) error
+	// SetLocked locks or unlocks a room
+	SetLocked(roomID string, locked bool) error
+	// SetParent marks a room as a breakout room of parentID
+	SetParent(roomID, parentID string) error
+	// SetConfig makes a room persistent, creating it if needed. A nil cfg
+	// makes it ad-hoc again, and it is deleted if it has no members.
+	SetConfig(roomID string, cfg *model.RoomConfig) error
+	// FindClient returns the room ID and client for a client ID
+	FindClient(clientID string) (roomID string, c *model.Client, err error)
+	// GetClient returns a member of a room
+	GetClient(roomID, clientID string) (*model.Client, error)
+	// Clients returns a snapshot of the members of a room
+	Clients(roomID string) ([]*model.Client, error)
+	// Participants returns the members of a room that are not virtual, as
+	// announced to the other members. Shared implementations include the
+	// members connected to other pods.
+	Participants(roomID string) ([]model.Participant, error)
+	// UpdateParticipant stores the current profile and state of a member
+	// where Participants reads them
+	UpdateParticipant(c *model.Client) error
+	// IsMember reports whether a client is a member of any room. Shared
+	// implementations include the members connected to other pods.
+	IsMember(clientID string) (bool, error)
 }
 
+// JoinMode says how AddClient treats the host role and the room lock
+type JoinMode int
+
+const (
+	// JoinAsMember joins unlocked rooms only
+	JoinAsMember JoinMode = iota
+	// JoinAsHost makes the client host and ignores the lock
+	JoinAsHost
+	// JoinAdmitted ignores the lock for a client the host admitted
+	JoinAdmitted
+)
+
 var (
-	ErrNotFound = errors.New("room not found")
+	ErrNotFound    = errors.New("room not found")
+	ErrRoomLocked  = errors.New("room is locked")
+	ErrRoomFull    = errors.New("room is full")
+	ErrRoomExpired = errors.New("room has expired")
 )
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"time"
+
+	"gosignaling/model"
+)
+
+// SessionLog defines the interface for the durable record of rooms and of
+// the sessions of their members, for billing and support. Records are
+// written by the room repository as clients join and leave.
+type SessionLog interface {
+	// Rooms returns the room records matching a filter, newest first
+	Rooms(f HistoryFilter) ([]*model.RoomRecord, error)
+	// Sessions returns the session records matching a filter, newest first
+	Sessions(f HistoryFilter) ([]*model.SessionRecord, error)
+}
+
+// HistoryFilter selects records of the session log. Empty fields match all
+// records; From and To bound when a room was created or a session joined.
+type HistoryFilter struct {
+	RoomID   string
+	ClientID string // sessions only
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
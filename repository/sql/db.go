@@ -0,0 +1,136 @@
+package sql
+
+import (
+	stdsql "database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" driver
+	_ "modernc.org/sqlite"             // registers the "sqlite" driver
+)
+
+// Databases accepted by Open
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// migrations are applied in order and recorded in schema_migrations; a
+// released migration must never change. Statements must work on both SQLite
+// and PostgreSQL, and timestamps are written in UTC.
+var migrations = []struct {
+	version    int
+	statements []string
+}{
+	{1, []string{
+		`CREATE TABLE rooms (
+			room_id TEXT PRIMARY KEY,
+			config TEXT NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE TABLE room_log (
+			id TEXT PRIMARY KEY,
+			room_id TEXT NOT NULL,
+			node TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			closed_at TIMESTAMP
+		)`,
+		`CREATE INDEX room_log_room_id ON room_log (room_id, created_at)`,
+		`CREATE TABLE session_log (
+			id TEXT PRIMARY KEY,
+			room_log_id TEXT NOT NULL,
+			room_id TEXT NOT NULL,
+			client_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			is_virtual BOOLEAN NOT NULL,
+			node TEXT NOT NULL,
+			joined_at TIMESTAMP NOT NULL,
+			left_at TIMESTAMP
+		)`,
+		`CREATE INDEX session_log_room_id ON session_log (room_id, joined_at)`,
+		`CREATE INDEX session_log_client_id ON session_log (client_id, joined_at)`,
+	}},
+}
+
+// Open connects to a SQLite or PostgreSQL database and migrates its schema.
+// For SQLite the DSN is a file path, for PostgreSQL a connection URL.
+func Open(driver, dsn string) (*stdsql.DB, error) {
+	var driverName string
+	switch driver {
+	case DriverSQLite:
+		driverName = "sqlite"
+	case DriverPostgres:
+		driverName = "pgx"
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+
+	db, err := stdsql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if driver == DriverSQLite {
+		// SQLite allows one writer at a time
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := migrate(db, driver); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return db, nil
+}
+
+// migrate applies the migrations the database has not seen yet
+func migrate(db *stdsql.DB, driver string) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Pods starting together wait for each other instead of migrating twice
+	if driver == DriverPostgres {
+		if _, err := tx.Exec(`LOCK TABLE schema_migrations IN EXCLUSIVE MODE`); err != nil {
+			return err
+		}
+	}
+
+	var current int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		for _, stmt := range m.statements {
+			if _, err := tx.Exec(stmt); err != nil {
+				return fmt.Errorf("migration %d: %w", m.version, err)
+			}
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)`, m.version, now()); err != nil {
+			return err
+		}
+		log.Printf("🗄️ Applied database migration %d", m.version)
+	}
+	return tx.Commit()
+}
+
+// now returns the current time as stored in the database
+func now() time.Time {
+	// PostgreSQL keeps microseconds
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
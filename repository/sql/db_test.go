@@ -0,0 +1,45 @@
+package sql
+
+import (
+	stdsql "database/sql"
+	"testing"
+)
+
+// openTest opens a migrated in-memory SQLite database; Open keeps a single
+// connection to it, which holds the data until the test ends
+func openTest(t *testing.T) *stdsql.DB {
+	t.Helper()
+	db, err := Open(DriverSQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMigrate(t *testing.T) {
+	db := openTest(t)
+
+	// A restarted pod finds the schema up to date
+	if err := migrate(db, DriverSQLite); err != nil {
+		t.Fatalf("second migration: %v", err)
+	}
+	var count, version int
+	if err := db.QueryRow(`SELECT COUNT(*), MAX(version) FROM schema_migrations`).Scan(&count, &version); err != nil {
+		t.Fatal(err)
+	}
+	if want := migrations[len(migrations)-1].version; count != len(migrations) || version != want {
+		t.Errorf("schema_migrations: %d rows up to version %d, want %d up to %d", count, version, len(migrations), want)
+	}
+	for _, table := range []string{"rooms", "room_log", "session_log"} {
+		if _, err := db.Exec(`SELECT COUNT(*) FROM ` + table); err != nil {
+			t.Errorf("table %s: %v", table, err)
+		}
+	}
+}
+
+func TestOpenUnsupportedDriver(t *testing.T) {
+	if _, err := Open("mysql", ""); err == nil {
+		t.Error("opened a database with an unsupported driver")
+	}
+}
@@ -0,0 +1,323 @@
+package sql
+
+import (
+	stdsql "database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"gosignaling/model"
+	"gosignaling/repository"
+
+	"github.com/rs/xid"
+)
+
+// roomRepository keeps the members of rooms in the local repository, which
+// only knows the clients connected to this pod. It stores the configuration
+// of persistent rooms in the database, shared by every pod and restart, and
+// records rooms and sessions in the session log.
+type roomRepository struct {
+	repository.Room
+	db      *stdsql.DB
+	node    string
+	refresh *repository.ConfigRefresh
+
+	mutex    sync.Mutex
+	rooms    map[string]string // open room record ID by room ID
+	sessions map[string]string // open session record ID by client ID
+}
+
+// staleCheckInterval is how often a pod of a cluster closes the records of
+// pods that are gone
+const staleCheckInterval = time.Minute
+
+// NewRoomRepository creates a room repository that persists room
+// configurations and the session log in db and keeps members in local. node
+// identifies this pod in the session log. liveNodes lists the pods of the
+// cluster that are still running, so that the records the others left open
+// are closed; nil means this pod is the only one.
+func NewRoomRepository(db *stdsql.DB, local repository.Room, node string, liveNodes func() ([]string, error)) repository.Room {
+	r := &roomRepository{
+		Room:     local,
+		db:       db,
+		node:     node,
+		refresh:  repository.NewConfigRefresh(repository.ConfigRefreshInterval),
+		rooms:    make(map[string]string),
+		sessions: make(map[string]string),
+	}
+
+	// Node IDs change on every start, so records left open by a stopped pod
+	// are closed by the pods still running
+	if liveNodes == nil {
+		r.closeStale(nil)
+	} else {
+		go func() {
+			for {
+				if live, err := liveNodes(); err != nil {
+					log.Printf("Failed to list cluster nodes: %v", err)
+				} else {
+					r.closeStale(live)
+				}
+				time.Sleep(staleCheckInterval)
+			}
+		}()
+	}
+
+	configs, err := r.loadAll()
+	if err != nil {
+		log.Printf("Failed to load persistent rooms: %v", err)
+	}
+	for roomID, cfg := range configs {
+		local.SetConfig(roomID, cfg)
+	}
+	if len(configs) > 0 {
+		log.Printf("🏠 Loaded %d persistent rooms", len(configs))
+	}
+	return r
+}
+
+// Get retrieves a room by ID with its current configuration
+func (r *roomRepository) Get(roomID string) (*model.Room, error) {
+	r.sync(roomID)
+	return r.Room.Get(roomID)
+}
+
+// Delete removes a room and ends the sessions of its members
+func (r *roomRepository) Delete(roomID string) error {
+	clients, _ := r.Room.Clients(roomID)
+	if err := r.Room.Delete(roomID); err != nil {
+		return err
+	}
+	for _, c := range clients {
+		r.recordLeave(c.ID)
+	}
+	r.recordClose(roomID)
+	return nil
+}
+
+// ListPersistent returns the persistent rooms of all pods, with the members
+// on this pod
+func (r *roomRepository) ListPersistent() ([]*model.Room, error) {
+	configs, err := r.loadAll()
+	if err != nil {
+		return nil, err
+	}
+	rooms := make([]*model.Room, 0, len(configs))
+	for roomID, cfg := range configs {
+		room, err := r.Room.Get(roomID)
+		if err != nil {
+			room = model.NewRoom(roomID)
+		}
+		room.Config = cfg
+		rooms = append(rooms, room)
+	}
+	return rooms, nil
+}
+
+// AddClient adds a client to a room after picking up configuration changes
+// made on other pods, and records the session
+func (r *roomRepository) AddClient(roomID string, c *model.Client, mode repository.JoinMode) (bool, error) {
+	r.sync(roomID)
+
+	prevRoomID, _, prevErr := r.Room.FindClient(c.ID)
+	created, err := r.Room.AddClient(roomID, c, mode)
+	if err != nil || (prevErr == nil && prevRoomID == roomID) {
+		return created, err
+	}
+
+	// AddClient takes the client out of its previous room
+	if prevErr == nil {
+		r.recordLeave(c.ID)
+		r.closeIfEmpty(prevRoomID)
+	}
+	r.recordJoin(roomID, c)
+	return created, nil
+}
+
+// RemoveClient removes a client from its room and ends its session
+func (r *roomRepository) RemoveClient(clientID string) (string, bool, error) {
+	roomID, deleted, err := r.Room.RemoveClient(clientID)
+	if err != nil {
+		return roomID, deleted, err
+	}
+	r.recordLeave(clientID)
+	r.closeIfEmpty(roomID)
+	return roomID, deleted, nil
+}
+
+// SetConfig stores or removes the configuration of a room
+func (r *roomRepository) SetConfig(roomID string, cfg *model.RoomConfig) error {
+	if cfg == nil {
+		if _, err := r.db.Exec(`DELETE FROM rooms WHERE room_id = $1`, roomID); err != nil {
+			return err
+		}
+	} else {
+		data, err := json.Marshal(cfg)
+		if err != nil {
+			return err
+		}
+		_, err = r.db.Exec(`INSERT INTO rooms (room_id, config, updated_at) VALUES ($1, $2, $3)
+			ON CONFLICT (room_id) DO UPDATE SET config = excluded.config, updated_at = excluded.updated_at`,
+			roomID, string(data), now())
+		if err != nil {
+			return err
+		}
+	}
+	return r.Room.SetConfig(roomID, cfg)
+}
+
+// sync copies the stored configuration of a room to the local repository,
+// at most once per repository.ConfigRefreshInterval. Database errors are
+// logged and the local copy is used.
+func (r *roomRepository) sync(roomID string) {
+	if !r.refresh.Due(roomID, time.Now()) {
+		return
+	}
+	var (
+		cfg  *model.RoomConfig
+		data string
+	)
+	err := r.db.QueryRow(`SELECT config FROM rooms WHERE room_id = $1`, roomID).Scan(&data)
+	switch {
+	case err == stdsql.ErrNoRows:
+	case err != nil:
+		log.Printf("Failed to load configuration of room %s: %v", roomID, err)
+		r.refresh.Forget(roomID)
+		return
+	default:
+		cfg = &model.RoomConfig{}
+		if err := json.Unmarshal([]byte(data), cfg); err != nil {
+			log.Printf("Invalid configuration of room %s: %v", roomID, err)
+			return
+		}
+	}
+
+	room, err := r.Room.Get(roomID)
+	if err == repository.ErrNotFound && cfg == nil {
+		return
+	}
+	if err == nil && room.Config.Equal(cfg) {
+		return
+	}
+	r.Room.SetConfig(roomID, cfg)
+}
+
+// loadAll returns the stored configurations that have not expired
+func (r *roomRepository) loadAll() (map[string]*model.RoomConfig, error) {
+	rows, err := r.db.Query(`SELECT room_id, config FROM rooms`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	t := time.Now()
+	configs := make(map[string]*model.RoomConfig)
+	for rows.Next() {
+		var roomID, data string
+		if err := rows.Scan(&roomID, &data); err != nil {
+			return nil, err
+		}
+		var cfg model.RoomConfig
+		if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+			log.Printf("Invalid configuration of room %s: %v", roomID, err)
+			continue
+		}
+		// Expired rooms are removed by the sweeper once it closed them
+		if !cfg.Expired(t) {
+			configs[roomID] = &cfg
+		}
+	}
+	return configs, rows.Err()
+}
+
+// closeStale ends the room and session records of every pod but this one
+// and those in live
+func (r *roomRepository) closeStale(live []string) {
+	args := []interface{}{now()}
+	var placeholders []string
+	for _, node := range append([]string{r.node}, live...) {
+		args = append(args, node)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+	notLive := "node NOT IN (" + strings.Join(placeholders, ", ") + ")"
+
+	if _, err := r.db.Exec(`UPDATE session_log SET left_at = $1 WHERE left_at IS NULL AND `+notLive, args...); err != nil {
+		log.Printf("Failed to close stale sessions: %v", err)
+	}
+	if _, err := r.db.Exec(`UPDATE room_log SET closed_at = $1 WHERE closed_at IS NULL AND `+notLive, args...); err != nil {
+		log.Printf("Failed to close stale rooms: %v", err)
+	}
+}
+
+// recordJoin starts the session of a client, and the room record when the
+// client is the room's first member. Failures are logged; they must not keep
+// clients out of rooms.
+func (r *roomRepository) recordJoin(roomID string, c *model.Client) {
+	t := now()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	roomRecordID, ok := r.rooms[roomID]
+	if !ok {
+		roomRecordID = xid.New().String()
+		_, err := r.db.Exec(`INSERT INTO room_log (id, room_id, node, created_at) VALUES ($1, $2, $3, $4)`,
+			roomRecordID, roomID, r.node, t)
+		if err != nil {
+			log.Printf("Failed to record room %s: %v", roomID, err)
+			return
+		}
+		r.rooms[roomID] = roomRecordID
+	}
+
+	sessionID := xid.New().String()
+	_, err := r.db.Exec(`INSERT INTO session_log (id, room_log_id, room_id, client_id, name, is_virtual, node, joined_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		sessionID, roomRecordID, roomID, c.ID, c.Profile().Name, c.Virtual, r.node, t)
+	if err != nil {
+		log.Printf("Failed to record session of %s in room %s: %v", c.ID, roomID, err)
+		return
+	}
+	r.sessions[c.ID] = sessionID
+}
+
+// recordLeave ends the session of a client
+func (r *roomRepository) recordLeave(clientID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sessionID, ok := r.sessions[clientID]
+	if !ok {
+		return
+	}
+	delete(r.sessions, clientID)
+	if _, err := r.db.Exec(`UPDATE session_log SET left_at = $1 WHERE id = $2`, now(), sessionID); err != nil {
+		log.Printf("Failed to record %s leaving: %v", clientID, err)
+	}
+}
+
+// closeIfEmpty ends the room record once the room has no members on this
+// pod; persistent rooms are kept empty but are closed in the log
+func (r *roomRepository) closeIfEmpty(roomID string) {
+	if clients, err := r.Room.Clients(roomID); err == nil && len(clients) > 0 {
+		return
+	}
+	r.recordClose(roomID)
+}
+
+// recordClose ends the room record of a room
+func (r *roomRepository) recordClose(roomID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	roomRecordID, ok := r.rooms[roomID]
+	if !ok {
+		return
+	}
+	delete(r.rooms, roomID)
+	if _, err := r.db.Exec(`UPDATE room_log SET closed_at = $1 WHERE id = $2`, now(), roomRecordID); err != nil {
+		log.Printf("Failed to record room %s closing: %v", roomID, err)
+	}
+}
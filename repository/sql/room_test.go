@@ -0,0 +1,214 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"gosignaling/model"
+	"gosignaling/repository"
+	"gosignaling/repository/mem"
+)
+
+// TestSessionLog checks the room and session records written as clients
+// join, move between rooms and leave
+func TestSessionLog(t *testing.T) {
+	db := openTest(t)
+	r := NewRoomRepository(db, mem.NewRoomRepository(), "pod-1", nil)
+	history := NewSessionLog(db)
+
+	alice := model.NewClient("user")
+	alice.SetProfile(model.Profile{Name: "Alice"})
+	bob := model.NewClient("user")
+	for _, c := range []*model.Client{alice, bob} {
+		if _, err := r.AddClient("room", c, repository.JoinAsMember); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Joining another room ends the session in the first one
+	if _, err := r.AddClient("other", bob, repository.JoinAsMember); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.RemoveClient(alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	rooms, err := history.Rooms(repository.HistoryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := map[string]bool{}
+	for _, rec := range rooms {
+		closed[rec.RoomID] = rec.ClosedAt != nil
+	}
+	if len(rooms) != 2 || !closed["room"] || closed["other"] {
+		t.Errorf("room records: got %+v, want room closed and other open", closed)
+	}
+
+	sessions, err := history.Sessions(repository.HistoryFilter{RoomID: "room"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("sessions in room: got %d, want 2", len(sessions))
+	}
+	for _, rec := range sessions {
+		if rec.LeftAt == nil {
+			t.Errorf("session of %s in room is still open", rec.ClientID)
+		}
+		if rec.ClientID == alice.ID && rec.Name != "Alice" {
+			t.Errorf("session of Alice has name %q", rec.Name)
+		}
+	}
+
+	// A restarted pod, with a new node ID, closes the records it left open
+	NewRoomRepository(db, mem.NewRoomRepository(), "pod-2", nil)
+	sessions, err = history.Sessions(repository.HistoryFilter{ClientID: bob.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range sessions {
+		if rec.LeftAt == nil {
+			t.Errorf("session of bob in %s is still open after a restart", rec.RoomID)
+		}
+	}
+}
+
+// TestCloseStale checks that a pod of a cluster closes the records of pods
+// that are gone and keeps those of pods still running
+func TestCloseStale(t *testing.T) {
+	db := openTest(t)
+	history := NewSessionLog(db)
+	// The first check of each pod finds them all running
+	all := func() ([]string, error) { return []string{"gone", "running", "new"}, nil }
+	for _, node := range []string{"gone", "running"} {
+		r := NewRoomRepository(db, mem.NewRoomRepository(), node, all)
+		if _, err := r.AddClient(node, model.NewClient("user"), repository.JoinAsMember); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRoomRepository(db, mem.NewRoomRepository(), "new", all).(*roomRepository)
+	r.closeStale([]string{"new", "running"})
+
+	rooms, err := history.Rooms(repository.HistoryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := history.Sessions(repository.HistoryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range rooms {
+		if closed := rec.ClosedAt != nil; closed != (rec.RoomID == "gone") {
+			t.Errorf("room record of %s closed: %v", rec.RoomID, closed)
+		}
+	}
+	for _, rec := range sessions {
+		if left := rec.LeftAt != nil; left != (rec.RoomID == "gone") {
+			t.Errorf("session in %s ended: %v", rec.RoomID, left)
+		}
+	}
+	if len(rooms) != 2 || len(sessions) != 2 {
+		t.Errorf("got %d room records and %d sessions, want 2 of each", len(rooms), len(sessions))
+	}
+}
+
+// TestHistoryFilter checks the filters, order and limit of the history queries
+func TestHistoryFilter(t *testing.T) {
+	db := openTest(t)
+	history := NewSessionLog(db)
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, rec := range []struct{ room, client string }{
+		{"a", "alice"}, {"a", "bob"}, {"b", "alice"}, {"b", "carol"},
+	} {
+		joined := base.Add(time.Duration(i) * time.Hour)
+		_, err := db.Exec(`INSERT INTO session_log (id, room_log_id, room_id, client_id, name, is_virtual, node, joined_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			string(rune('0'+i)), "log-"+rec.room, rec.room, rec.client, "", false, "node", joined)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter repository.HistoryFilter
+		want   []string // session IDs, newest first
+	}{
+		{"all", repository.HistoryFilter{}, []string{"3", "2", "1", "0"}},
+		{"room", repository.HistoryFilter{RoomID: "a"}, []string{"1", "0"}},
+		{"client", repository.HistoryFilter{ClientID: "alice"}, []string{"2", "0"}},
+		{"room and client", repository.HistoryFilter{RoomID: "b", ClientID: "alice"}, []string{"2"}},
+		{"from", repository.HistoryFilter{From: base.Add(2 * time.Hour)}, []string{"3", "2"}},
+		{"to", repository.HistoryFilter{To: base.Add(time.Hour)}, []string{"0"}},
+		{"limit", repository.HistoryFilter{Limit: 1}, []string{"3"}},
+	}
+	for _, tt := range tests {
+		records, err := history.Sessions(tt.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for _, rec := range records {
+			got = append(got, rec.ID)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+	if records, _ := history.Sessions(repository.HistoryFilter{}); len(records) > 0 && !records[0].JoinedAt.Equal(base.Add(3*time.Hour)) {
+		t.Errorf("joined_at: got %v, want %v", records[0].JoinedAt, base.Add(3*time.Hour))
+	}
+}
+
+// TestConfigSync checks that a pod picks up the configuration another pod
+// stored once its copy is due for a refresh, and keeps an unchanged copy
+func TestConfigSync(t *testing.T) {
+	db := openTest(t)
+	a := NewRoomRepository(db, mem.NewRoomRepository(), "a", nil).(*roomRepository)
+	b := NewRoomRepository(db, mem.NewRoomRepository(), "b", nil).(*roomRepository)
+
+	if _, err := b.Get("room"); err != repository.ErrNotFound {
+		t.Fatalf("room before configuration: got %v, want ErrNotFound", err)
+	}
+	cfg := &model.RoomConfig{Capacity: 5, Topology: model.TopologySFU, CreatedAt: time.Now()}
+	if err := a.SetConfig("room", cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// The miss above is cached for the refresh interval
+	if _, err := b.Get("room"); err != repository.ErrNotFound {
+		t.Errorf("room within the refresh interval: got %v, want ErrNotFound", err)
+	}
+
+	b.refresh = repository.NewConfigRefresh(0)
+	room, err := b.Get("room")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !room.Config.Equal(cfg) {
+		t.Fatalf("configuration: got %+v, want %+v", room.Config, cfg)
+	}
+	again, err := b.Get("room")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Config != room.Config {
+		t.Error("an unchanged configuration was replaced")
+	}
+
+	// The stored copy of the writing pod's configuration lost its monotonic
+	// clock reading and location, but is the same configuration
+	a.refresh = repository.NewConfigRefresh(0)
+	if room, err := a.Get("room"); err != nil || room.Config != cfg {
+		t.Errorf("configuration of the writing pod was replaced by its stored copy (%v)", err)
+	}
+}
@@ -0,0 +1,128 @@
+package sql
+
+import (
+	stdsql "database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+const (
+	defaultHistoryLimit = 100
+	maxHistoryLimit     = 1000
+)
+
+type sessionLog struct {
+	db *stdsql.DB
+}
+
+// NewSessionLog creates a session log reading the records written by the
+// room repository to db
+func NewSessionLog(db *stdsql.DB) repository.SessionLog {
+	return &sessionLog{db: db}
+}
+
+// Rooms returns the room records matching a filter, newest first
+func (s *sessionLog) Rooms(f repository.HistoryFilter) ([]*model.RoomRecord, error) {
+	where, args := historyWhere(f, "created_at", false)
+	rows, err := s.db.Query(`SELECT id, room_id, node, created_at, closed_at FROM room_log`+
+		where+` ORDER BY created_at DESC, id DESC`+historyLimit(f), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*model.RoomRecord{}
+	for rows.Next() {
+		var (
+			rec      model.RoomRecord
+			closedAt stdsql.NullTime
+		)
+		if err := rows.Scan(&rec.ID, &rec.RoomID, &rec.Node, &rec.CreatedAt, &closedAt); err != nil {
+			return nil, err
+		}
+		rec.CreatedAt = rec.CreatedAt.UTC()
+		rec.ClosedAt = nullTime(closedAt)
+		records = append(records, &rec)
+	}
+	return records, rows.Err()
+}
+
+// Sessions returns the session records matching a filter, newest first
+func (s *sessionLog) Sessions(f repository.HistoryFilter) ([]*model.SessionRecord, error) {
+	where, args := historyWhere(f, "joined_at", true)
+	rows, err := s.db.Query(`SELECT id, room_log_id, room_id, client_id, name, is_virtual, node, joined_at, left_at FROM session_log`+
+		where+` ORDER BY joined_at DESC, id DESC`+historyLimit(f), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*model.SessionRecord{}
+	for rows.Next() {
+		var (
+			rec    model.SessionRecord
+			leftAt stdsql.NullTime
+		)
+		err := rows.Scan(&rec.ID, &rec.RoomRecordID, &rec.RoomID, &rec.ClientID, &rec.Name,
+			&rec.Virtual, &rec.Node, &rec.JoinedAt, &leftAt)
+		if err != nil {
+			return nil, err
+		}
+		rec.JoinedAt = rec.JoinedAt.UTC()
+		rec.LeftAt = nullTime(leftAt)
+		records = append(records, &rec)
+	}
+	return records, rows.Err()
+}
+
+// historyWhere builds the WHERE clause of a filter, bounding timeColumn by
+// From and To
+func historyWhere(f repository.HistoryFilter, timeColumn string, byClient bool) (string, []interface{}) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.RoomID != "" {
+		add("room_id = $%d", f.RoomID)
+	}
+	if byClient && f.ClientID != "" {
+		add("client_id = $%d", f.ClientID)
+	}
+	if !f.From.IsZero() {
+		add(timeColumn+" >= $%d", f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		add(timeColumn+" < $%d", f.To.UTC())
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func historyLimit(f repository.HistoryFilter) string {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+	return fmt.Sprintf(" LIMIT %d", limit)
+}
+
+func nullTime(t stdsql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	utc := t.Time.UTC()
+	return &utc
+}
@@ -1,45 +1,180 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"time"
 
 	"gosignaling/config"
 	"gosignaling/handler"
 	"gosignaling/manager"
+	"gosignaling/repository"
 	"gosignaling/repository/mem"
+	redisrepo "gosignaling/repository/redis"
+	sqlrepo "gosignaling/repository/sql"
 	"gosignaling/services"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 func serve(addr string) error {
+	// Persistent rooms are stored in the database if there is one, else in
+	// Redis when clustered. The database also keeps the session log.
 	roomRepo := mem.NewRoomRepository()
-	roomManager := manager.NewRoomManager(roomRepo)
-	h := handler.NewHandler(roomManager)
+	var sessionLog repository.SessionLog
+	if config.Database.URL != "" {
+		db, err := sqlrepo.Open(config.Database.Driver, config.Database.URL)
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		defer db.Close()
+		log.Printf("🗄️ Using %s database", config.Database.Driver)
+		// Pods of a cluster close the records of pods that are gone
+		var liveNodes func() ([]string, error)
+		if config.Rdb != nil {
+			liveNodes = func() ([]string, error) { return redisrepo.LiveNodes(config.Rdb) }
+		}
+		roomRepo = sqlrepo.NewRoomRepository(db, roomRepo, config.NodeID, liveNodes)
+		sessionLog = sqlrepo.NewSessionLog(db)
+	} else if config.Rdb != nil {
+		roomRepo = redisrepo.NewRoomRepository(config.Rdb, roomRepo)
+	}
+	// Members of rooms on all pods are shared through Redis when clustered
+	if config.Rdb != nil {
+		roomRepo = redisrepo.NewClusterRoomRepository(config.Rdb, roomRepo, config.NodeID)
+	}
+	// Chat history is shared through Redis when clustered
+	chatRepo := mem.NewChatRepository(config.Chat.HistorySize)
+	if config.Rdb != nil {
+		chatRepo = redisrepo.NewChatRepository(config.Rdb, config.Chat.HistorySize, config.Chat.HistoryTTL)
+	}
+	roomManager := manager.NewRoomManager(roomRepo, chatRepo, sessionLog)
+	rateLimiter := services.NewRateLimiter(config.RateLimit)
+	iceServers := services.NewIceServerService(config.ICE, config.TURN)
+	h := handler.NewHandler(roomManager, rateLimiter, iceServers)
 
 	// Initialize clustering service for multi-pod support (if Redis is available)
 	if config.Rdb != nil {
-		clusteringService := services.NewClusteringService(roomManager)
+		clusteringService := services.NewClusteringService(roomManager, h)
 		clusteringService.InitializeRedisSubscriptions()
+		h.SetSSERelay(clusteringService)
 		log.Println("✅ Redis Pub/Sub clustering initialized for WebRTC signaling")
 	} else {
 		log.Println("ℹ️ Running in standalone mode (no Redis clustering)")
 	}
 
+	// Embedded TURN relay; it also answers STUN binding requests on its port
+	if config.TURN.Enabled {
+		turnServer, err := services.NewTurnServer(config.TURN, config.ICE.TURNSecret)
+		if err != nil {
+			return err
+		}
+		defer turnServer.Close()
+	}
+
+	// Embedded STUN server for deployments without an external one
+	if config.ICE.EmbeddedSTUN && config.TURN.Enabled && config.ICE.STUNPort == config.TURN.Port {
+		log.Printf("ℹ️ STUN requests on port %d are answered by the embedded TURN server", config.TURN.Port)
+	} else if config.ICE.EmbeddedSTUN {
+		stunServer, err := services.NewStunServer(fmt.Sprintf(":%d", config.ICE.STUNPort))
+		if err != nil {
+			return err
+		}
+		defer stunServer.Close()
+		go func() {
+			if err := stunServer.Serve(); err != nil {
+				log.Printf("STUN server stopped: %v", err)
+			}
+		}()
+	}
+
+	// gRPC signaling API on its own port, sharing rooms with the HTTP transports
+	if config.GRPC.Enabled {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", config.GRPC.Port))
+		if err != nil {
+			return err
+		}
+		grpcServer := grpc.NewServer(
+			grpc.MaxRecvMsgSize(int(config.Signaling.MaxMessageSize)),
+			grpc.UnaryInterceptor(handler.GRPCAdminInterceptor),
+			grpc.KeepaliveParams(keepalive.ServerParameters{
+				Time:    config.Signaling.PingInterval,
+				Timeout: config.Signaling.PongWait - config.Signaling.PingInterval,
+			}),
+			// Mobile clients often enable their own keepalive pings
+			grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+				MinTime:             10 * time.Second,
+				PermitWithoutStream: true,
+			}),
+		)
+		h.RegisterGRPC(grpcServer)
+		defer grpcServer.Stop()
+		go func() {
+			log.Printf("📡 gRPC signaling server listening on %s", lis.Addr())
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Printf("gRPC server stopped: %v", err)
+			}
+		}()
+	}
+
+	// The default mux is not served: expvar registers /debug/vars on it
+	mux := http.NewServeMux()
+
 	// Health check endpoint for Fly.io
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
 
-	http.HandleFunc("/connect", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/connect", func(w http.ResponseWriter, r *http.Request) {
 		h.CreateConnection(w, r)
 	})
 
 	// Add /ws endpoint as alias for /connect
-	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		h.CreateConnection(w, r)
 	})
 
+	// Server-Sent Events + HTTP POST fallback for networks that block WebSockets
+	mux.HandleFunc("/sse", func(w http.ResponseWriter, r *http.Request) {
+		h.HandleSSE(w, r)
+	})
+	mux.HandleFunc("/sse/", func(w http.ResponseWriter, r *http.Request) {
+		h.HandleSSE(w, r)
+	})
+
+	// WHIP ingest: POST /whip/{room}, PATCH and DELETE /whip/{room}/{session}
+	mux.HandleFunc("/whip/", func(w http.ResponseWriter, r *http.Request) {
+		h.HandleWHIP(w, r)
+	})
+
+	// WHEP egress: POST /whep/{room}, PATCH and DELETE /whep/{room}/{session}
+	mux.HandleFunc("/whep/", func(w http.ResponseWriter, r *http.Request) {
+		h.HandleWHEP(w, r)
+	})
+
+	// Admin API for persistent rooms, the session log and metrics, enabled by ADMIN_TOKEN
+	mux.HandleFunc("/admin/rooms", func(w http.ResponseWriter, r *http.Request) {
+		h.HandleAdminRooms(w, r)
+	})
+	mux.HandleFunc("/admin/rooms/", func(w http.ResponseWriter, r *http.Request) {
+		h.HandleAdminRooms(w, r)
+	})
+	mux.HandleFunc("/admin/history/", func(w http.ResponseWriter, r *http.Request) {
+		h.HandleAdminHistory(w, r)
+	})
+	mux.HandleFunc("/admin/metrics", func(w http.ResponseWriter, r *http.Request) {
+		h.HandleAdminMetrics(w, r)
+	})
+
+	// ICE servers (STUN and TURN credentials) for clients
+	mux.HandleFunc("/ice-servers", func(w http.ResponseWriter, r *http.Request) {
+		h.GetIceServers(w, r)
+	})
+
 	log.Printf("WebRTC signaling server listening on %s", addr)
-	return http.ListenAndServe(addr, nil)
+	return http.ListenAndServe(addr, mux)
 }
@@ -3,6 +3,7 @@ package services
 import (
 	"encoding/json"
 	"log"
+	"time"
 
 	"gosignaling/config"
 	"gosignaling/model"
@@ -13,18 +14,39 @@ import (
 // ClusteringService handles Redis Pub/Sub for multi-pod WebRTC signaling
 type ClusteringService struct {
 	roomManager RoomManagerInterface
+	sse         SSEReceiver
 }
 
 // RoomManagerInterface defines methods needed from RoomManager
 type RoomManagerInterface interface {
 	GetClientByID(clientID string) (*model.Client, error)
 	GetRoomByClientID(clientID string) (*model.Room, error)
+	DeliverToRoom(roomID, excludeClientID string, msg *model.Message)
+	MoveClient(clientID, fromRoomID, toRoomID, parentID string) error
+	RecallBreakouts(parentID string)
+	WarnRoomExpiring(roomID string, expiresAt time.Time, reason string) bool
+	CloseRoom(roomID, reason string)
+	HandleLobby(roomID, clientID, action, reason string)
+	Disconnect(c *model.Client, msg *model.Message) error
+}
+
+// SSEReceiver processes the messages POSTed on another pod for the SSE
+// sessions of this pod
+type SSEReceiver interface {
+	ReceiveSSEMessage(sessionID string, msgBytes []byte)
+}
+
+// ssePayload is the payload of a webrtc:sse message
+type ssePayload struct {
+	SessionID string `json:"session_id"`
+	Message   []byte `json:"message"` // the POSTed body, which may not be valid JSON
 }
 
 // NewClusteringService creates a new clustering service
-func NewClusteringService(rm RoomManagerInterface) *ClusteringService {
+func NewClusteringService(rm RoomManagerInterface, sse SSEReceiver) *ClusteringService {
 	return &ClusteringService{
 		roomManager: rm,
+		sse:         sse,
 	}
 }
 
@@ -36,6 +58,12 @@ func (cs *ClusteringService) InitializeRedisSubscriptions() {
 		string(model.RedisMessageTypeIceCandidate),
 		string(model.RedisMessageTypeNewClient),
 		string(model.RedisMessageTypeLeaveClient),
+		string(model.RedisMessageTypeBroadcast),
+		string(model.RedisMessageTypeDirect),
+		string(model.RedisMessageTypeMove),
+		string(model.RedisMessageTypeExpire),
+		string(model.RedisMessageTypeLobby),
+		string(model.RedisMessageTypeSSE),
 	)
 
 	log.Println("📡 Subscribed to Redis Pub/Sub channels for WebRTC signaling clustering")
@@ -50,12 +78,42 @@ func (cs *ClusteringService) InitializeRedisSubscriptions() {
 
 // handleRedisMessage processes incoming Redis Pub/Sub messages
 func (cs *ClusteringService) handleRedisMessage(msg *redis.Message) {
-	var redisMsg model.RedisMessage
-	if err := json.Unmarshal([]byte(msg.Payload), &redisMsg); err != nil {
+	redisMsg, err := model.DecodeRedisMessage([]byte(msg.Payload))
+	if err != nil {
 		log.Printf("❌ Failed to unmarshal Redis message: %v", err)
 		return
 	}
 
+	// Broadcasts have no target; they go to the room's members on this pod
+	if msg.Channel == string(model.RedisMessageTypeBroadcast) {
+		cs.handleBroadcast(redisMsg)
+		return
+	}
+
+	// Moves look up their client in the room it is moved from
+	if msg.Channel == string(model.RedisMessageTypeMove) {
+		cs.handleMove(redisMsg)
+		return
+	}
+
+	// Expiry applies to the room's members on this pod
+	if msg.Channel == string(model.RedisMessageTypeExpire) {
+		cs.handleExpire(redisMsg)
+		return
+	}
+
+	// Lobby answers apply to the clients waiting on this pod
+	if msg.Channel == string(model.RedisMessageTypeLobby) {
+		cs.handleLobby(redisMsg)
+		return
+	}
+
+	// SSE messages are addressed to a session, not a client
+	if msg.Channel == string(model.RedisMessageTypeSSE) {
+		cs.handleSSE(redisMsg)
+		return
+	}
+
 	// Get target client (only handle if client is on this pod)
 	targetClient, err := cs.roomManager.GetClientByID(redisMsg.TargetClientID)
 	if err != nil {
@@ -65,15 +123,17 @@ func (cs *ClusteringService) handleRedisMessage(msg *redis.Message) {
 
 	switch msg.Channel {
 	case string(model.RedisMessageTypeSDPOffer):
-		cs.handleSDPOffer(targetClient, redisMsg)
+		cs.handleSDPOffer(targetClient, *redisMsg)
 	case string(model.RedisMessageTypeSDPAnswer):
-		cs.handleSDPAnswer(targetClient, redisMsg)
+		cs.handleSDPAnswer(targetClient, *redisMsg)
 	case string(model.RedisMessageTypeIceCandidate):
-		cs.handleIceCandidate(targetClient, redisMsg)
+		cs.handleIceCandidate(targetClient, *redisMsg)
 	case string(model.RedisMessageTypeNewClient):
-		cs.handleNewClient(targetClient, redisMsg)
+		cs.handleNewClient(targetClient, *redisMsg)
 	case string(model.RedisMessageTypeLeaveClient):
-		cs.handleLeaveClient(targetClient, redisMsg)
+		cs.handleLeaveClient(targetClient, *redisMsg)
+	case string(model.RedisMessageTypeDirect):
+		cs.handleDirect(targetClient, *redisMsg)
 	default:
 		log.Printf("⚠️ Unknown Redis channel: %s", msg.Channel)
 	}
@@ -154,9 +214,152 @@ func (cs *ClusteringService) handleLeaveClient(targetClient *model.Client, redis
 	}
 }
 
+// handleBroadcast delivers a room-wide message from another pod to the local room members
+func (cs *ClusteringService) handleBroadcast(redisMsg *model.RedisMessage) {
+	// The publishing pod has already delivered to its own members
+	if redisMsg.Node == config.NodeID {
+		return
+	}
+	// Pods that do not set Node yet only publish for their own members
+	if redisMsg.Node == "" {
+		if _, err := cs.roomManager.GetClientByID(redisMsg.SenderClientID); err == nil {
+			return
+		}
+	}
+
+	msgType := redisMsg.MessageType
+	if msgType == "" {
+		msgType = model.MessageTypeBroadcast
+	}
+	cs.roomManager.DeliverToRoom(redisMsg.RoomID, redisMsg.SenderClientID, &model.Message{
+		Type:    msgType,
+		Payload: redisMsg.Payload,
+	})
+}
+
+// handleDirect handles a message for one client from Redis
+func (cs *ClusteringService) handleDirect(targetClient *model.Client, redisMsg model.RedisMessage) {
+	// Direct messages are only delivered within the sender's room
+	room, err := cs.roomManager.GetRoomByClientID(targetClient.ID)
+	if err != nil || room.ID != redisMsg.RoomID {
+		return
+	}
+
+	msgType := redisMsg.MessageType
+	if msgType == "" {
+		msgType = model.MessageTypeDirect
+	}
+	msg := &model.Message{
+		Type:    msgType,
+		Payload: redisMsg.Payload,
+	}
+
+	// Kicks are carried out here, as on the pod of the host
+	if msg.Disconnects() {
+		cs.roomManager.Disconnect(targetClient, msg)
+		log.Printf("📤 Disconnected client %s with %s message from Redis", targetClient.ID, msgType)
+		return
+	}
+
+	select {
+	case targetClient.Send <- msg:
+		log.Printf("📤 Forwarded %s message from Redis to client %s", msgType, targetClient.ID)
+	default:
+		log.Printf("⚠️ Failed to send %s message to client %s", msgType, targetClient.ID)
+	}
+}
+
+// handleMove moves a client on this pod between rooms, or recalls the
+// breakout rooms of a room when the message has no target
+func (cs *ClusteringService) handleMove(redisMsg *model.RedisMessage) {
+	var payload struct {
+		RoomID       string `json:"room_id"`
+		ParentRoomID string `json:"parent_room_id"`
+	}
+	if err := json.Unmarshal(redisMsg.Payload, &payload); err != nil {
+		log.Printf("❌ Failed to unmarshal move payload: %v", err)
+		return
+	}
+
+	if redisMsg.TargetClientID == "" {
+		cs.roomManager.RecallBreakouts(redisMsg.RoomID)
+		return
+	}
+	if err := cs.roomManager.MoveClient(redisMsg.TargetClientID, redisMsg.RoomID, payload.RoomID, payload.ParentRoomID); err == nil {
+		log.Printf("📤 Moved client %s to room %s for another pod", redisMsg.TargetClientID, payload.RoomID)
+	}
+}
+
+// handleExpire warns or closes a room whose expiry another pod's sweeper found
+func (cs *ClusteringService) handleExpire(redisMsg *model.RedisMessage) {
+	var payload struct {
+		ExpiresAt time.Time `json:"expires_at"`
+		Reason    string    `json:"reason"`
+	}
+	if err := json.Unmarshal(redisMsg.Payload, &payload); err != nil {
+		log.Printf("❌ Failed to unmarshal expiry payload: %v", err)
+		return
+	}
+
+	switch redisMsg.MessageType {
+	case model.MessageTypeRoomExpiring:
+		cs.roomManager.WarnRoomExpiring(redisMsg.RoomID, payload.ExpiresAt, payload.Reason)
+	case model.MessageTypeRoomClosed:
+		cs.roomManager.CloseRoom(redisMsg.RoomID, payload.Reason)
+	default:
+		log.Printf("⚠️ Unknown expiry message type: %s", redisMsg.MessageType)
+	}
+}
+
+// handleLobby admits, denies or presents clients waiting on this pod for a
+// room whose host is on another pod
+func (cs *ClusteringService) handleLobby(redisMsg *model.RedisMessage) {
+	// The publishing pod has already applied it to its own lobby
+	if redisMsg.Node == config.NodeID {
+		return
+	}
+	var payload struct {
+		Action string `json:"action"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(redisMsg.Payload, &payload); err != nil {
+		log.Printf("❌ Failed to unmarshal lobby payload: %v", err)
+		return
+	}
+	cs.roomManager.HandleLobby(redisMsg.RoomID, redisMsg.TargetClientID, payload.Action, payload.Reason)
+}
+
+// handleSSE processes a message that another pod received for an SSE
+// session; only the pod with the session's stream acts on it
+func (cs *ClusteringService) handleSSE(redisMsg *model.RedisMessage) {
+	if redisMsg.Node == config.NodeID || cs.sse == nil {
+		return
+	}
+	var payload ssePayload
+	if err := json.Unmarshal(redisMsg.Payload, &payload); err != nil {
+		log.Printf("❌ Failed to unmarshal SSE payload: %v", err)
+		return
+	}
+	cs.sse.ReceiveSSEMessage(payload.SessionID, payload.Message)
+}
+
+// PublishSSEMessage hands a message POSTed for an SSE session that is not on
+// this pod to the other pods
+func (cs *ClusteringService) PublishSSEMessage(sessionID string, msgBytes []byte) error {
+	payload, err := json.Marshal(ssePayload{SessionID: sessionID, Message: msgBytes})
+	if err != nil {
+		return err
+	}
+	return cs.PublishToRedis(model.RedisMessageTypeSSE, &model.RedisMessage{
+		Type:    model.RedisMessageTypeSSE,
+		Payload: payload,
+	})
+}
+
 // PublishToRedis publishes a message to Redis Pub/Sub
 func (cs *ClusteringService) PublishToRedis(channel model.RedisMessageType, redisMsg *model.RedisMessage) error {
-	msgBytes, err := json.Marshal(redisMsg)
+	redisMsg.Node = config.NodeID
+	msgBytes, err := redisMsg.Encode(config.RedisEncoding)
 	if err != nil {
 		return err
 	}
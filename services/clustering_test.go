@@ -0,0 +1,168 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/model"
+	"gosignaling/repository"
+)
+
+// fakeRoomManager records the room-wide deliveries of the clustering service
+type fakeRoomManager struct {
+	local     map[string]*model.Client
+	delivered []*model.Message
+	lobby     []string
+}
+
+func (f *fakeRoomManager) GetClientByID(clientID string) (*model.Client, error) {
+	if c, ok := f.local[clientID]; ok {
+		return c, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+// GetRoomByClientID puts every local client in room "room"
+func (f *fakeRoomManager) GetRoomByClientID(clientID string) (*model.Room, error) {
+	if _, ok := f.local[clientID]; ok {
+		return model.NewRoom("room"), nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeRoomManager) DeliverToRoom(roomID, excludeClientID string, msg *model.Message) {
+	f.delivered = append(f.delivered, msg)
+}
+
+func (f *fakeRoomManager) MoveClient(string, string, string, string) error { return nil }
+func (f *fakeRoomManager) RecallBreakouts(string)                          {}
+func (f *fakeRoomManager) WarnRoomExpiring(string, time.Time, string) bool { return false }
+func (f *fakeRoomManager) CloseRoom(string, string)                        {}
+
+func (f *fakeRoomManager) Disconnect(c *model.Client, msg *model.Message) error {
+	c.Disconnect(msg)
+	return nil
+}
+
+func (f *fakeRoomManager) HandleLobby(roomID, clientID, action, reason string) {
+	f.lobby = append(f.lobby, roomID+" "+clientID+" "+action+" "+reason)
+}
+
+// TestHandleBroadcastSkipsOwnNode checks that a pod does not deliver its own
+// room-wide messages twice, whoever the sender is
+func TestHandleBroadcastSkipsOwnNode(t *testing.T) {
+	local := model.NewClient("alice")
+	tests := []struct {
+		name    string
+		msg     model.RedisMessage
+		deliver bool
+	}{
+		{"own node", model.RedisMessage{Node: config.NodeID, SenderClientID: "remote"}, false},
+		{"other node", model.RedisMessage{Node: "other", SenderClientID: "remote"}, true},
+		{"other node, sender here", model.RedisMessage{Node: "other", SenderClientID: local.ID}, true},
+		{"no node, sender here", model.RedisMessage{SenderClientID: local.ID}, false},
+		{"no node, sender elsewhere", model.RedisMessage{SenderClientID: "remote"}, true},
+	}
+	for _, tt := range tests {
+		rm := &fakeRoomManager{local: map[string]*model.Client{local.ID: local}}
+		msg := tt.msg
+		msg.Type = model.RedisMessageTypeBroadcast
+		msg.MessageType = model.MessageTypeNewClient
+		NewClusteringService(rm, nil).handleBroadcast(&msg)
+
+		if delivered := len(rm.delivered) > 0; delivered != tt.deliver {
+			t.Errorf("%s: delivered %v, want %v", tt.name, delivered, tt.deliver)
+		}
+	}
+}
+
+// TestHandleLobby checks that lobby actions of other pods reach the manager
+// with their target and reason, and that a pod ignores its own
+func TestHandleLobby(t *testing.T) {
+	payload := []byte(`{"action":"deny","reason":"full"}`)
+	tests := []struct {
+		name string
+		node string
+		want []string
+	}{
+		{"own node", config.NodeID, nil},
+		{"other node", "other", []string{"room alice deny full"}},
+	}
+	for _, tt := range tests {
+		rm := &fakeRoomManager{}
+		NewClusteringService(rm, nil).handleLobby(&model.RedisMessage{
+			Type:           model.RedisMessageTypeLobby,
+			TargetClientID: "alice",
+			RoomID:         "room",
+			Payload:        payload,
+			Node:           tt.node,
+		})
+
+		if len(rm.lobby) != len(tt.want) || (len(tt.want) > 0 && rm.lobby[0] != tt.want[0]) {
+			t.Errorf("%s: handled %q, want %q", tt.name, rm.lobby, tt.want)
+		}
+	}
+}
+
+// TestHandleDirectKick checks that a kick from another pod disconnects its
+// target even when the target's send buffer is full
+func TestHandleDirectKick(t *testing.T) {
+	target := model.NewClient("bob")
+	for len(target.Send) < cap(target.Send) {
+		target.Send <- &model.Message{Type: model.MessageTypeBroadcast}
+	}
+	rm := &fakeRoomManager{local: map[string]*model.Client{target.ID: target}}
+	NewClusteringService(rm, nil).handleDirect(target, model.RedisMessage{
+		Type:           model.RedisMessageTypeDirect,
+		MessageType:    model.MessageTypeKicked,
+		TargetClientID: target.ID,
+		RoomID:         "room",
+		Payload:        []byte(`{"client_id":"host","reason":"spam"}`),
+	})
+
+	select {
+	case <-target.Disconnected():
+	default:
+		t.Fatal("target was not disconnected")
+	}
+	if msg := target.Farewell(); msg.Type != model.MessageTypeKicked {
+		t.Errorf("farewell %s, want kicked", msg.Type)
+	}
+}
+
+// fakeSSEReceiver records the SSE messages handed to the pod
+type fakeSSEReceiver struct {
+	received []string
+}
+
+func (f *fakeSSEReceiver) ReceiveSSEMessage(sessionID string, msgBytes []byte) {
+	f.received = append(f.received, sessionID+" "+string(msgBytes))
+}
+
+// TestHandleSSE checks that a message POSTed on another pod reaches the SSE
+// sessions of this one unchanged, even when it is not valid JSON
+func TestHandleSSE(t *testing.T) {
+	payload, _ := json.Marshal(ssePayload{SessionID: "s1", Message: []byte(`{"type":"ping"`)})
+	tests := []struct {
+		name string
+		node string
+		want []string
+	}{
+		{"own node", config.NodeID, nil},
+		{"other node", "other", []string{`s1 {"type":"ping"`}},
+	}
+	for _, tt := range tests {
+		sse := &fakeSSEReceiver{}
+		NewClusteringService(&fakeRoomManager{}, sse).handleSSE(&model.RedisMessage{
+			Type:    model.RedisMessageTypeSSE,
+			Payload: payload,
+			Node:    tt.node,
+		})
+
+		if len(sse.received) != len(tt.want) || (len(tt.want) > 0 && sse.received[0] != tt.want[0]) {
+			t.Errorf("%s: received %q, want %q", tt.name, sse.received, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Host tokens let an application backend choose a room's host. A token is
+// "<expiry>:<signature>", where expiry is a Unix timestamp and signature is
+// base64url(HMAC-SHA256(secret, "<expiry>:<room ID>")).
+
+var (
+	errInvalidHostToken = errors.New("invalid host token")
+	errExpiredHostToken = errors.New("host token expired")
+)
+
+// HostToken creates a host token for a room
+func HostToken(secret, roomID string, expiresAt time.Time) string {
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expiry + ":" + hostTokenSignature(secret, expiry, roomID)
+}
+
+// VerifyHostToken checks that a host token was issued for a room and has not expired
+func VerifyHostToken(secret, roomID, token string, now time.Time) error {
+	if secret == "" {
+		return errInvalidHostToken
+	}
+	expiry, signature, ok := strings.Cut(token, ":")
+	if !ok {
+		return errInvalidHostToken
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return errInvalidHostToken
+	}
+	if !hmac.Equal([]byte(signature), []byte(hostTokenSignature(secret, expiry, roomID))) {
+		return errInvalidHostToken
+	}
+	if now.Unix() > expiresAt {
+		return errExpiredHostToken
+	}
+	return nil
+}
+
+func hostTokenSignature(secret, expiry, roomID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(expiry + ":" + roomID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
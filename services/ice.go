@@ -0,0 +1,104 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"gosignaling/config"
+	"gosignaling/model"
+)
+
+// IceServerService issues the ICE server list handed to clients, minting
+// time-limited TURN credentials with the TURN REST API scheme used by
+// coturn's use-auth-secret: the username is "<expiry>:<user>" and the
+// credential is base64(HMAC-SHA1(secret, username)).
+type IceServerService struct {
+	cfg  config.ICEConfig
+	turn config.TURNServerConfig
+}
+
+// NewIceServerService creates a new ICE server service
+func NewIceServerService(cfg config.ICEConfig, turn config.TURNServerConfig) *IceServerService {
+	return &IceServerService{
+		cfg:  cfg,
+		turn: turn,
+	}
+}
+
+// IceServers returns the ICE servers for userID, with TURN credentials valid
+// for the configured TTL. requestHost is the host the client connected to and
+// is advertised for embedded servers when no public host is configured.
+func (s *IceServerService) IceServers(userID, requestHost string) []model.IceServer {
+	var servers []model.IceServer
+	stunURLs := s.cfg.STUNURLs
+	if s.cfg.EmbeddedSTUN {
+		if host := s.publicHost(requestHost); host != "" {
+			embedded := fmt.Sprintf("stun:%s", net.JoinHostPort(host, strconv.Itoa(s.cfg.STUNPort)))
+			stunURLs = append([]string{embedded}, stunURLs...)
+		}
+	}
+	if len(stunURLs) > 0 {
+		servers = append(servers, model.IceServer{URLs: stunURLs})
+	}
+	turnURLs := s.cfg.TURNURLs
+	if s.turn.Enabled {
+		if host := s.publicHost(requestHost); host != "" {
+			turnURLs = append(s.embeddedTURNURLs(host), turnURLs...)
+		}
+	}
+	if len(turnURLs) > 0 && s.cfg.TURNSecret != "" {
+		username, credential := TURNCredentials(s.cfg.TURNSecret, userID, time.Now().Add(s.cfg.TURNTTL))
+		servers = append(servers, model.IceServer{
+			URLs:       turnURLs,
+			Username:   username,
+			Credential: credential,
+		})
+	}
+	return servers
+}
+
+// embeddedTURNURLs lists the transports of the embedded TURN server
+func (s *IceServerService) embeddedTURNURLs(host string) []string {
+	addr := net.JoinHostPort(host, strconv.Itoa(s.turn.Port))
+	urls := []string{
+		fmt.Sprintf("turn:%s?transport=udp", addr),
+		fmt.Sprintf("turn:%s?transport=tcp", addr),
+	}
+	if s.turn.TLSEnabled() {
+		urls = append(urls, fmt.Sprintf("turns:%s?transport=tcp", net.JoinHostPort(host, strconv.Itoa(s.turn.TLSPort))))
+	}
+	return urls
+}
+
+// publicHost returns the host name clients use to reach embedded servers
+func (s *IceServerService) publicHost(requestHost string) string {
+	if s.cfg.PublicHost != "" {
+		return s.cfg.PublicHost
+	}
+	host, _, err := net.SplitHostPort(requestHost)
+	if err != nil {
+		return requestHost
+	}
+	return host
+}
+
+// TTL returns the lifetime of issued TURN credentials
+func (s *IceServerService) TTL() time.Duration {
+	return s.cfg.TURNTTL
+}
+
+// TURNCredentials computes the TURN REST API username and credential for userID
+func TURNCredentials(secret, userID string, expiresAt time.Time) (username, credential string) {
+	username = strconv.FormatInt(expiresAt.Unix(), 10)
+	if userID != "" {
+		username += ":" + userID
+	}
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(username))
+	return username, base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"gosignaling/config"
+)
+
+// violationWindow is the period over which rejected messages are counted
+const violationWindow = time.Minute
+
+var (
+	// ErrTooManyConnections is returned when an IP exceeds its connection cap
+	ErrTooManyConnections = errors.New("too many connections from this address")
+)
+
+// tokenBucket is a simple token bucket; callers must hold the owning lock
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(r config.Rate, now time.Time) *tokenBucket {
+	if r.PerSecond <= 0 {
+		return nil
+	}
+	return &tokenBucket{
+		rate:   r.PerSecond,
+		burst:  float64(r.Burst),
+		tokens: float64(r.Burst),
+		last:   now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	// Callers read the clock before taking the lock and may arrive out of order
+	if now.Before(b.last) {
+		return
+	}
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) allow(now time.Time) bool {
+	return b.allowN(now, 1)
+}
+
+func (b *tokenBucket) allowN(now time.Time, n float64) bool {
+	if b == nil {
+		return true
+	}
+	b.refill(now)
+	if b.tokens < n {
+		return false
+	}
+	b.tokens -= n
+	return true
+}
+
+// available reports whether the bucket holds a token without taking it
+func (b *tokenBucket) available(now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	b.refill(now)
+	return b.tokens >= 1
+}
+
+// take removes a token that available reported
+func (b *tokenBucket) take() {
+	if b != nil {
+		b.tokens--
+	}
+}
+
+func (b *tokenBucket) full(now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	b.refill(now)
+	return b.tokens >= b.burst
+}
+
+// ipState tracks the shared bucket and open connections of a source IP
+type ipState struct {
+	bucket      *tokenBucket
+	connections int
+}
+
+// RateLimiter enforces per-IP and per-connection signaling limits
+type RateLimiter struct {
+	cfg   config.RateLimitConfig
+	mutex sync.Mutex
+	ips   map[string]*ipState
+}
+
+// NewRateLimiter creates a rate limiter and starts its cleanup loop
+func NewRateLimiter(cfg config.RateLimitConfig) *RateLimiter {
+	rl := &RateLimiter{
+		cfg: cfg,
+		ips: make(map[string]*ipState),
+	}
+	if cfg.Enabled {
+		go rl.cleanupLoop(time.Minute)
+	}
+	return rl
+}
+
+// AcquireConnection reserves a connection slot for the given IP
+func (rl *RateLimiter) AcquireConnection(ip string) error {
+	if !rl.cfg.Enabled {
+		return nil
+	}
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	state := rl.ipStateLocked(ip)
+	if rl.cfg.MaxConnectionsPerIP > 0 && state.connections >= rl.cfg.MaxConnectionsPerIP {
+		return ErrTooManyConnections
+	}
+	state.connections++
+	return nil
+}
+
+// ReleaseConnection frees a connection slot previously acquired for the IP
+func (rl *RateLimiter) ReleaseConnection(ip string) {
+	if !rl.cfg.Enabled {
+		return
+	}
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	if state, ok := rl.ips[ip]; ok && state.connections > 0 {
+		state.connections--
+	}
+}
+
+// NewClientLimiter creates the per-connection limiter for a client from ip
+func (rl *RateLimiter) NewClientLimiter(ip string) *ClientLimiter {
+	now := time.Now()
+	cl := &ClientLimiter{
+		parent: rl,
+		ip:     ip,
+		client: newTokenBucket(rl.cfg.Client, now),
+		byType: make(map[string]*tokenBucket, len(rl.cfg.ByType)),
+	}
+	for msgType, r := range rl.cfg.ByType {
+		cl.byType[msgType] = newTokenBucket(r, now)
+	}
+	return cl
+}
+
+// AllowRequest reports whether an IP may make a request outside a signaling
+// connection; it takes a token from the IP's shared bucket
+func (rl *RateLimiter) AllowRequest(ip string) bool {
+	if !rl.cfg.Enabled {
+		return true
+	}
+	return rl.allowIP(ip, time.Now())
+}
+
+// allowIP takes a token from the shared bucket of an IP
+func (rl *RateLimiter) allowIP(ip string, now time.Time) bool {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	return rl.ipStateLocked(ip).bucket.allow(now)
+}
+
+func (rl *RateLimiter) ipStateLocked(ip string) *ipState {
+	state, ok := rl.ips[ip]
+	if !ok {
+		state = &ipState{bucket: newTokenBucket(rl.cfg.IP, time.Now())}
+		rl.ips[ip] = state
+	}
+	return state
+}
+
+// cleanupLoop forgets IPs without connections whose bucket has refilled
+func (rl *RateLimiter) cleanupLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		rl.cleanup(now)
+	}
+}
+
+// cleanup forgets the IPs that have nothing left to limit
+func (rl *RateLimiter) cleanup(now time.Time) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	for ip, state := range rl.ips {
+		if state.connections == 0 && state.bucket.full(now) {
+			delete(rl.ips, ip)
+		}
+	}
+}
+
+// ClientLimiter limits the messages of a single connection. Its mutex is
+// taken before the RateLimiter's.
+type ClientLimiter struct {
+	parent      *RateLimiter
+	ip          string
+	mutex       sync.Mutex
+	client      *tokenBucket
+	byType      map[string]*tokenBucket
+	violations  int
+	windowStart time.Time
+}
+
+// Allow reports whether a message of msgType may be processed now. A token
+// is taken from the message type's, the connection's and the IP's buckets
+// only if all of them have one, so rejected messages cost nothing.
+func (cl *ClientLimiter) Allow(msgType string) bool {
+	if !cl.parent.cfg.Enabled {
+		return true
+	}
+	return cl.allow(msgType, time.Now())
+}
+
+func (cl *ClientLimiter) allow(msgType string, now time.Time) bool {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	rl := cl.parent
+	rl.mutex.Lock()
+	buckets := []*tokenBucket{cl.byType[msgType], cl.client, rl.ipStateLocked(cl.ip).bucket}
+	allowed := true
+	for _, b := range buckets {
+		allowed = allowed && b.available(now)
+	}
+	if allowed {
+		for _, b := range buckets {
+			b.take()
+		}
+	}
+	rl.mutex.Unlock()
+
+	if !allowed {
+		// Violations are counted per window so that a client that keeps
+		// pushing above its limit is eventually disconnected
+		if now.Sub(cl.windowStart) > violationWindow {
+			cl.windowStart = now
+			cl.violations = 0
+		}
+		cl.violations++
+		log.Printf("🚦 Rate limited %s message from %s (%d violations)", msgType, cl.ip, cl.violations)
+	}
+	return allowed
+}
+
+// Exceeded reports whether the connection should be dropped for abuse
+func (cl *ClientLimiter) Exceeded() bool {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	return cl.parent.cfg.MaxViolations > 0 && cl.violations >= cl.parent.cfg.MaxViolations
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"gosignaling/config"
+)
+
+func TestTokenBucketRefill(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(config.Rate{PerSecond: 2, Burst: 3}, now)
+
+	for i := 0; i < 3; i++ {
+		if !b.allow(now) {
+			t.Fatalf("token %d of the burst was rejected", i+1)
+		}
+	}
+	if b.allow(now) {
+		t.Error("token beyond the burst was allowed")
+	}
+	if !b.allow(now.Add(500 * time.Millisecond)) {
+		t.Error("token refilled after half a second at 2/s was rejected")
+	}
+	if b.allow(now.Add(500 * time.Millisecond)) {
+		t.Error("second token after half a second was allowed")
+	}
+	// A long pause refills up to the burst only
+	later := now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		b.allow(later)
+	}
+	if b.allow(later) {
+		t.Error("refill exceeded the burst")
+	}
+	if nb := newTokenBucket(config.Rate{}, now); nb != nil || !nb.allow(now) {
+		t.Error("a zero rate does not disable the bucket")
+	}
+}
+
+func newTestLimiter(cfg config.RateLimitConfig) *RateLimiter {
+	cfg.Enabled = true
+	// Refills are slow enough not to matter within a test
+	return &RateLimiter{cfg: cfg, ips: make(map[string]*ipState)}
+}
+
+// TestRejectedMessagesCostNothing checks that a message rejected by one
+// bucket takes no token from the others
+func TestRejectedMessagesCostNothing(t *testing.T) {
+	slow := config.Rate{PerSecond: 0.001, Burst: 2}
+	rl := newTestLimiter(config.RateLimitConfig{
+		Client: config.Rate{PerSecond: 0.001, Burst: 3},
+		ByType: map[string]config.Rate{"join": slow},
+	})
+	cl := rl.NewClientLimiter("10.0.0.1")
+	now := time.Now()
+
+	// The per-type bucket rejects the third join, the client bucket still
+	// has a token for another type
+	for i, want := range []bool{true, true, false, false} {
+		if got := cl.allow("join", now); got != want {
+			t.Fatalf("join %d: allowed %v, want %v", i+1, got, want)
+		}
+	}
+	if !cl.allow("offer", now) {
+		t.Error("rejected joins used up the client budget")
+	}
+	if cl.allow("offer", now) {
+		t.Error("client bucket allowed more than its burst")
+	}
+
+	// Rejected by the client bucket, the per-type bucket keeps its token
+	other := rl.NewClientLimiter("10.0.0.2")
+	for i := 0; i < 3; i++ {
+		other.allow("offer", now)
+	}
+	other.allow("join", now)
+	other.client.tokens = 3
+	if !other.allow("join", now) || !other.allow("join", now) {
+		t.Error("a join rejected by the client bucket used up the join budget")
+	}
+	if other.violations != 1 {
+		t.Errorf("violations: got %d, want 1", other.violations)
+	}
+}
+
+// TestIPBucketSharedByConnections checks that connections from one IP share
+// its bucket, and that rejections by it leave their own buckets untouched
+func TestIPBucketSharedByConnections(t *testing.T) {
+	rl := newTestLimiter(config.RateLimitConfig{
+		Client: config.Rate{PerSecond: 0.001, Burst: 5},
+		IP:     config.Rate{PerSecond: 0.001, Burst: 3},
+	})
+	a := rl.NewClientLimiter("10.0.0.1")
+	b := rl.NewClientLimiter("10.0.0.1")
+	c := rl.NewClientLimiter("10.0.0.2")
+	now := time.Now()
+
+	a.allow("offer", now)
+	a.allow("offer", now)
+	if !b.allow("offer", now) || b.allow("offer", now) {
+		t.Error("the IP bucket is not shared by its connections")
+	}
+	if b.client.tokens != 4 {
+		t.Errorf("client tokens after a rejection by the IP: got %v, want 4", b.client.tokens)
+	}
+	if !c.allow("offer", now) {
+		t.Error("another IP was limited")
+	}
+}
+
+func TestConnectionsPerIP(t *testing.T) {
+	rl := newTestLimiter(config.RateLimitConfig{MaxConnectionsPerIP: 2})
+	for i := 0; i < 2; i++ {
+		if err := rl.AcquireConnection("10.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rl.AcquireConnection("10.0.0.1"); err != ErrTooManyConnections {
+		t.Errorf("third connection: got %v, want ErrTooManyConnections", err)
+	}
+	rl.ReleaseConnection("10.0.0.1")
+	if err := rl.AcquireConnection("10.0.0.1"); err != nil {
+		t.Errorf("connection after a release: %v", err)
+	}
+}
+
+// TestCleanupEvictsIdleIPs checks that IPs are forgotten once they have no
+// connections and a full bucket
+func TestCleanupEvictsIdleIPs(t *testing.T) {
+	rl := newTestLimiter(config.RateLimitConfig{IP: config.Rate{PerSecond: 1, Burst: 2}})
+	now := time.Now()
+	rl.AcquireConnection("connected")
+	rl.allowIP("drained", now)
+	rl.AcquireConnection("idle")
+	rl.ReleaseConnection("idle")
+
+	rl.cleanup(now)
+	for ip, want := range map[string]bool{"connected": true, "drained": true, "idle": false} {
+		if _, kept := rl.ips[ip]; kept != want {
+			t.Errorf("%s: kept %v, want %v", ip, kept, want)
+		}
+	}
+	rl.cleanup(now.Add(time.Minute))
+	if _, kept := rl.ips["drained"]; kept {
+		t.Error("refilled IP was kept")
+	}
+}
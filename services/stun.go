@@ -0,0 +1,93 @@
+package services
+
+import (
+	"expvar"
+	"log"
+	"net"
+
+	"github.com/pion/stun/v3"
+)
+
+// STUN server metrics, published on /admin/metrics
+var (
+	stunBindingRequests = expvar.NewInt("stun_binding_requests")
+	stunBindingErrors   = expvar.NewInt("stun_binding_errors")
+	stunInvalidPackets  = expvar.NewInt("stun_invalid_packets")
+)
+
+// StunServer is a minimal RFC 5389 STUN server that answers Binding
+// requests with the XOR-MAPPED-ADDRESS of the sender
+type StunServer struct {
+	conn net.PacketConn
+}
+
+// NewStunServer creates a STUN server listening on the given UDP address
+func NewStunServer(addr string) (*StunServer, error) {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &StunServer{
+		conn: conn,
+	}, nil
+}
+
+// Serve answers Binding requests until the server is closed
+func (s *StunServer) Serve() error {
+	log.Printf("📡 STUN server listening on udp %s", s.conn.LocalAddr())
+
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		s.handlePacket(buf[:n], addr)
+	}
+}
+
+// Close stops the STUN server
+func (s *StunServer) Close() error {
+	return s.conn.Close()
+}
+
+// handlePacket answers a single STUN Binding request
+func (s *StunServer) handlePacket(data []byte, addr net.Addr) {
+	if !stun.IsMessage(data) {
+		stunInvalidPackets.Add(1)
+		return
+	}
+
+	req := new(stun.Message)
+	if err := stun.Decode(data, req); err != nil {
+		stunInvalidPackets.Add(1)
+		return
+	}
+	if req.Type != stun.BindingRequest {
+		// Indications and responses are never answered
+		stunInvalidPackets.Add(1)
+		return
+	}
+	stunBindingRequests.Add(1)
+
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		stunBindingErrors.Add(1)
+		return
+	}
+
+	resp, err := stun.Build(req, stun.BindingSuccess,
+		&stun.XORMappedAddress{IP: udpAddr.IP, Port: udpAddr.Port},
+		stun.NewSoftware("gosignaling"),
+		stun.Fingerprint,
+	)
+	if err != nil {
+		stunBindingErrors.Add(1)
+		log.Printf("⚠️ Failed to build STUN response for %s: %v", addr, err)
+		return
+	}
+	if _, err := s.conn.WriteTo(resp.Raw, addr); err != nil {
+		stunBindingErrors.Add(1)
+		log.Printf("⚠️ Failed to send STUN response to %s: %v", addr, err)
+	}
+}
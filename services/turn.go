@@ -0,0 +1,289 @@
+package services
+
+import (
+	"crypto/tls"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"gosignaling/config"
+
+	"github.com/pion/turn/v4"
+)
+
+// TURN server metrics, published on /admin/metrics
+var (
+	turnActiveAllocations = expvar.NewInt("turn_active_allocations")
+	turnAllocationsTotal  = expvar.NewInt("turn_allocations_total")
+	turnAuthFailures      = expvar.NewInt("turn_auth_failures")
+	turnQuotaRejections   = expvar.NewInt("turn_quota_rejections")
+	turnThrottledBytes    = expvar.NewInt("turn_throttled_bytes")
+)
+
+var errTCPRelayUnsupported = errors.New("TCP relay allocations are not supported")
+
+// reservationTimeout releases the quota slot of an allocation that failed
+// after passing the quota check; pion creates it right after the check
+const reservationTimeout = 5 * time.Second
+
+// TurnServer is an embedded TURN relay (RFC 8656) that accepts the
+// time-limited credentials issued by IceServerService. Clients can reach it
+// over UDP, TCP and TLS; relayed transport addresses are UDP.
+type TurnServer struct {
+	cfg    config.TURNServerConfig
+	server *turn.Server
+
+	mutex       sync.Mutex
+	allocations map[string]int                   // user -> active and reserved allocations
+	reserved    map[string]allocationReservation // client address -> reservation
+}
+
+// allocationReservation holds a quota slot from the quota check until the
+// allocation is created
+type allocationReservation struct {
+	user string
+	at   time.Time
+}
+
+// NewTurnServer starts the TURN listeners described by cfg
+func NewTurnServer(cfg config.TURNServerConfig, secret string) (*TurnServer, error) {
+	relayIP := net.ParseIP(cfg.RelayIP)
+	if relayIP == nil {
+		return nil, fmt.Errorf("TURN_RELAY_IP must be the public IP address of this host, got %q", cfg.RelayIP)
+	}
+
+	ts := &TurnServer{
+		cfg:         cfg,
+		allocations: make(map[string]int),
+		reserved:    make(map[string]allocationReservation),
+	}
+	relay := &throttledRelayGenerator{
+		RelayAddressGenerator: &turn.RelayAddressGeneratorPortRange{
+			RelayAddress: relayIP,
+			Address:      "0.0.0.0",
+			MinPort:      uint16(cfg.MinPort),
+			MaxPort:      uint16(cfg.MaxPort),
+		},
+		bytesPerSecond: cfg.BandwidthLimit,
+	}
+
+	addr := fmt.Sprintf(":%d", cfg.Port)
+	udpConn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	listeners := []net.Listener{}
+	tcpListener, err := net.Listen("tcp", addr)
+	if err != nil {
+		udpConn.Close()
+		return nil, err
+	}
+	listeners = append(listeners, tcpListener)
+
+	if cfg.TLSEnabled() {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+		if err != nil {
+			udpConn.Close()
+			tcpListener.Close()
+			return nil, err
+		}
+		tlsListener, err := tls.Listen("tcp", fmt.Sprintf(":%d", cfg.TLSPort), &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		})
+		if err != nil {
+			udpConn.Close()
+			tcpListener.Close()
+			return nil, err
+		}
+		listeners = append(listeners, tlsListener)
+	}
+
+	listenerConfigs := make([]turn.ListenerConfig, 0, len(listeners))
+	for _, l := range listeners {
+		listenerConfigs = append(listenerConfigs, turn.ListenerConfig{
+			Listener:              l,
+			RelayAddressGenerator: relay,
+		})
+	}
+
+	ts.server, err = turn.NewServer(turn.ServerConfig{
+		Realm:       cfg.Realm,
+		AuthHandler: turn.LongTermTURNRESTAuthHandler(secret, nil),
+		PacketConnConfigs: []turn.PacketConnConfig{{
+			PacketConn:            udpConn,
+			RelayAddressGenerator: relay,
+		}},
+		ListenerConfigs: listenerConfigs,
+		QuotaHandler:    ts.allowAllocation,
+		EventHandler: turn.EventHandler{
+			OnAuth:              ts.onAuth,
+			OnAllocationCreated: ts.onAllocationCreated,
+			OnAllocationDeleted: ts.onAllocationDeleted,
+		},
+	})
+	if err != nil {
+		udpConn.Close()
+		for _, l := range listeners {
+			l.Close()
+		}
+		return nil, err
+	}
+
+	log.Printf("🔁 TURN server listening on udp/tcp :%d (tls: %v)", cfg.Port, cfg.TLSEnabled())
+	return ts, nil
+}
+
+// Close stops the TURN server and releases all allocations
+func (ts *TurnServer) Close() error {
+	return ts.server.Close()
+}
+
+// allowAllocation enforces the per-user allocation quota. The slot is
+// reserved here, under the same lock as the check, and kept by
+// onAllocationCreated.
+func (ts *TurnServer) allowAllocation(username, realm string, srcAddr net.Addr) bool {
+	user := quotaUser(username)
+	addr := srcAddr.String()
+	now := time.Now()
+
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	ts.releaseFailedLocked(addr, now)
+	if ts.cfg.MaxAllocationsPerUser > 0 && ts.allocations[user] >= ts.cfg.MaxAllocationsPerUser {
+		turnQuotaRejections.Add(1)
+		log.Printf("⚠️ TURN allocation quota reached for %s (%s)", user, srcAddr)
+		return false
+	}
+	ts.allocations[user]++
+	ts.reserved[addr] = allocationReservation{user: user, at: now}
+	return true
+}
+
+// releaseFailedLocked releases the reservations of allocations that were
+// never created: those older than reservationTimeout, and an earlier one from
+// addr, which is retrying. Callers must hold the lock.
+func (ts *TurnServer) releaseFailedLocked(addr string, now time.Time) {
+	for a, res := range ts.reserved {
+		if a == addr || now.Sub(res.at) > reservationTimeout {
+			delete(ts.reserved, a)
+			ts.releaseLocked(res.user)
+		}
+	}
+}
+
+// releaseLocked frees an allocation slot of a user; callers must hold the lock
+func (ts *TurnServer) releaseLocked(user string) {
+	if ts.allocations[user]--; ts.allocations[user] <= 0 {
+		delete(ts.allocations, user)
+	}
+}
+
+// quotaUser returns the user a TURN REST username was issued to. The
+// username is "<expiry>:<user>", and every credential renewal changes the
+// expiry, so the quota is counted on the part after the colon.
+func quotaUser(username string) string {
+	if _, user, ok := strings.Cut(username, ":"); ok {
+		return user
+	}
+	return username
+}
+
+func (ts *TurnServer) onAuth(srcAddr, dstAddr net.Addr, protocol, username, realm, method string, verdict bool) {
+	if !verdict {
+		turnAuthFailures.Add(1)
+	}
+}
+
+func (ts *TurnServer) onAllocationCreated(srcAddr, dstAddr net.Addr, protocol, username, realm string, relayAddr net.Addr, requestedPort int) {
+	ts.mutex.Lock()
+	if _, ok := ts.reserved[srcAddr.String()]; ok {
+		delete(ts.reserved, srcAddr.String())
+	} else {
+		// The reservation timed out before the allocation was created
+		ts.allocations[quotaUser(username)]++
+	}
+	ts.mutex.Unlock()
+
+	turnActiveAllocations.Add(1)
+	turnAllocationsTotal.Add(1)
+	log.Printf("🔁 TURN allocation %s for %s over %s", relayAddr, username, protocol)
+}
+
+func (ts *TurnServer) onAllocationDeleted(srcAddr, dstAddr net.Addr, protocol, username, realm string) {
+	ts.mutex.Lock()
+	ts.releaseLocked(quotaUser(username))
+	ts.mutex.Unlock()
+
+	turnActiveAllocations.Add(-1)
+}
+
+// throttledRelayGenerator caps the bandwidth of every relayed allocation
+type throttledRelayGenerator struct {
+	turn.RelayAddressGenerator
+	bytesPerSecond int
+}
+
+// AllocatePacketConn allocates a UDP relay wrapped in a bandwidth limiter
+func (g *throttledRelayGenerator) AllocatePacketConn(network string, requestedPort int) (net.PacketConn, net.Addr, error) {
+	conn, addr, err := g.RelayAddressGenerator.AllocatePacketConn(network, requestedPort)
+	if err != nil || g.bytesPerSecond <= 0 {
+		return conn, addr, err
+	}
+	rate := config.Rate{PerSecond: float64(g.bytesPerSecond), Burst: g.bytesPerSecond}
+	now := time.Now()
+	return &throttledPacketConn{
+		PacketConn: conn,
+		in:         newTokenBucket(rate, now),
+		out:        newTokenBucket(rate, now),
+	}, addr, nil
+}
+
+// AllocateConn rejects TCP relays (RFC 6062), which are not supported
+func (g *throttledRelayGenerator) AllocateConn(string, int) (net.Conn, net.Addr, error) {
+	return nil, nil, errTCPRelayUnsupported
+}
+
+// throttledPacketConn drops relayed datagrams above the bandwidth limit,
+// the way a congested link would
+type throttledPacketConn struct {
+	net.PacketConn
+	mutex sync.Mutex
+	in    *tokenBucket // peer -> client
+	out   *tokenBucket // client -> peer
+}
+
+// ReadFrom reads the next datagram from a peer that fits in the budget
+func (c *throttledPacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	for {
+		n, addr, err := c.PacketConn.ReadFrom(p)
+		if err != nil || c.allow(c.in, n) {
+			return n, addr, err
+		}
+	}
+}
+
+// WriteTo relays a datagram to a peer if it fits in the budget
+func (c *throttledPacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	if !c.allow(c.out, len(p)) {
+		return len(p), nil
+	}
+	return c.PacketConn.WriteTo(p, addr)
+}
+
+func (c *throttledPacketConn) allow(b *tokenBucket, n int) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if b.allowN(time.Now(), float64(n)) {
+		return true
+	}
+	turnThrottledBytes.Add(int64(n))
+	return false
+}
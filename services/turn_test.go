@@ -0,0 +1,110 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gosignaling/config"
+)
+
+func newQuotaTestServer(max int) *TurnServer {
+	return &TurnServer{
+		cfg:         config.TURNServerConfig{MaxAllocationsPerUser: max},
+		allocations: make(map[string]int),
+		reserved:    make(map[string]allocationReservation),
+	}
+}
+
+func testAddr(port int) net.Addr {
+	return &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: port}
+}
+
+// allocate runs the quota check and, if it passes, creates the allocation
+func allocate(ts *TurnServer, username string, port int) bool {
+	if !ts.allowAllocation(username, "realm", testAddr(port)) {
+		return false
+	}
+	ts.onAllocationCreated(testAddr(port), nil, "udp", username, "realm", nil, 0)
+	return true
+}
+
+func TestTurnQuotaSharedAcrossCredentials(t *testing.T) {
+	ts := newQuotaTestServer(2)
+
+	// Renewed credentials only differ in their expiry
+	if !allocate(ts, "1700000000:alice", 1) || !allocate(ts, "1700086400:alice", 2) {
+		t.Fatal("allocations under the quota were rejected")
+	}
+	if allocate(ts, "1700172800:alice", 3) {
+		t.Error("a renewed credential got past the quota")
+	}
+	if !allocate(ts, "1700000000:bob", 4) {
+		t.Error("another user was rejected")
+	}
+
+	ts.onAllocationDeleted(testAddr(1), nil, "udp", "1700000000:alice", "realm")
+	if !allocate(ts, "1700172800:alice", 3) {
+		t.Error("a released slot was not reused")
+	}
+}
+
+func TestTurnQuotaReleasesFailedAllocations(t *testing.T) {
+	ts := newQuotaTestServer(1)
+
+	// The allocation fails after the quota check, and the client retries
+	if !ts.allowAllocation("1:alice", "realm", testAddr(1)) {
+		t.Fatal("first attempt rejected")
+	}
+	if !allocate(ts, "1:alice", 1) {
+		t.Fatal("the retry was rejected by its own failed attempt")
+	}
+	ts.onAllocationDeleted(testAddr(1), nil, "udp", "1:alice", "realm")
+
+	// A failed attempt that is never retried is released after a while
+	if !ts.allowAllocation("1:alice", "realm", testAddr(2)) {
+		t.Fatal("first attempt rejected")
+	}
+	ts.mutex.Lock()
+	res := ts.reserved[testAddr(2).String()]
+	res.at = res.at.Add(-2 * reservationTimeout)
+	ts.reserved[testAddr(2).String()] = res
+	ts.mutex.Unlock()
+	if !allocate(ts, "1:alice", 3) {
+		t.Error("the slot of a failed allocation was not released")
+	}
+	if n := ts.allocations["alice"]; n != 1 {
+		t.Errorf("%d allocations counted, want 1", n)
+	}
+}
+
+// TestTurnQuotaConcurrent checks that concurrent requests cannot all pass
+// the check before any of them is counted
+func TestTurnQuotaConcurrent(t *testing.T) {
+	const max = 3
+	ts := newQuotaTestServer(max)
+
+	var (
+		wg      sync.WaitGroup
+		allowed atomic.Int32
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			username := fmt.Sprintf("%d:alice", time.Now().Unix()+int64(i))
+			if ts.allowAllocation(username, "realm", testAddr(1000+i)) {
+				allowed.Add(1)
+				ts.onAllocationCreated(testAddr(1000+i), nil, "udp", username, "realm", nil, 0)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if n := allowed.Load(); n != max {
+		t.Errorf("%d allocations allowed, want %d", n, max)
+	}
+}
@@ -0,0 +1,4 @@
+// Package signalingpb contains the gRPC signaling API generated from signaling.proto.
+package signalingpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative signaling.proto
@@ -0,0 +1,332 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: signaling.proto
+
+package signalingpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Signaling_Signal_FullMethodName        = "/signaling.v1.Signaling/Signal"
+	Signaling_ListRooms_FullMethodName     = "/signaling.v1.Signaling/ListRooms"
+	Signaling_GetRoom_FullMethodName       = "/signaling.v1.Signaling/GetRoom"
+	Signaling_CloseRoom_FullMethodName     = "/signaling.v1.Signaling/CloseRoom"
+	Signaling_RemoveClient_FullMethodName  = "/signaling.v1.Signaling/RemoveClient"
+	Signaling_GetIceServers_FullMethodName = "/signaling.v1.Signaling/GetIceServers"
+)
+
+// SignalingClient is the client API for Signaling service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Signaling is the gRPC counterpart of the WebSocket API. Clients on either
+// transport share the same rooms.
+type SignalingClient interface {
+	// Signal is the signaling session of one client. The first server message
+	// is notify_client_id; the client leaves its room when the stream ends.
+	Signal(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ClientMessage, ServerMessage], error)
+	// ListRooms returns the rooms that have members on this server. Like the
+	// other room management RPCs, it needs the admin token as
+	// "authorization: Bearer <token>" metadata.
+	ListRooms(ctx context.Context, in *ListRoomsRequest, opts ...grpc.CallOption) (*ListRoomsResponse, error)
+	// GetRoom returns the members of a room on this server
+	GetRoom(ctx context.Context, in *GetRoomRequest, opts ...grpc.CallOption) (*Room, error)
+	// CloseRoom disconnects the members of a room on every server with a
+	// room-closed message
+	CloseRoom(ctx context.Context, in *CloseRoomRequest, opts ...grpc.CallOption) (*CloseRoomResponse, error)
+	// RemoveClient disconnects a member of a room with a kicked message
+	RemoveClient(ctx context.Context, in *RemoveClientRequest, opts ...grpc.CallOption) (*RemoveClientResponse, error)
+	// GetIceServers returns ICE servers with fresh TURN credentials
+	GetIceServers(ctx context.Context, in *GetIceServersRequest, opts ...grpc.CallOption) (*GetIceServersResponse, error)
+}
+
+type signalingClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignalingClient(cc grpc.ClientConnInterface) SignalingClient {
+	return &signalingClient{cc}
+}
+
+func (c *signalingClient) Signal(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ClientMessage, ServerMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Signaling_ServiceDesc.Streams[0], Signaling_Signal_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ClientMessage, ServerMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Signaling_SignalClient = grpc.BidiStreamingClient[ClientMessage, ServerMessage]
+
+func (c *signalingClient) ListRooms(ctx context.Context, in *ListRoomsRequest, opts ...grpc.CallOption) (*ListRoomsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRoomsResponse)
+	err := c.cc.Invoke(ctx, Signaling_ListRooms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) GetRoom(ctx context.Context, in *GetRoomRequest, opts ...grpc.CallOption) (*Room, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Room)
+	err := c.cc.Invoke(ctx, Signaling_GetRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) CloseRoom(ctx context.Context, in *CloseRoomRequest, opts ...grpc.CallOption) (*CloseRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseRoomResponse)
+	err := c.cc.Invoke(ctx, Signaling_CloseRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) RemoveClient(ctx context.Context, in *RemoveClientRequest, opts ...grpc.CallOption) (*RemoveClientResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveClientResponse)
+	err := c.cc.Invoke(ctx, Signaling_RemoveClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) GetIceServers(ctx context.Context, in *GetIceServersRequest, opts ...grpc.CallOption) (*GetIceServersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIceServersResponse)
+	err := c.cc.Invoke(ctx, Signaling_GetIceServers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignalingServer is the server API for Signaling service.
+// All implementations must embed UnimplementedSignalingServer
+// for forward compatibility.
+//
+// Signaling is the gRPC counterpart of the WebSocket API. Clients on either
+// transport share the same rooms.
+type SignalingServer interface {
+	// Signal is the signaling session of one client. The first server message
+	// is notify_client_id; the client leaves its room when the stream ends.
+	Signal(grpc.BidiStreamingServer[ClientMessage, ServerMessage]) error
+	// ListRooms returns the rooms that have members on this server. Like the
+	// other room management RPCs, it needs the admin token as
+	// "authorization: Bearer <token>" metadata.
+	ListRooms(context.Context, *ListRoomsRequest) (*ListRoomsResponse, error)
+	// GetRoom returns the members of a room on this server
+	GetRoom(context.Context, *GetRoomRequest) (*Room, error)
+	// CloseRoom disconnects the members of a room on every server with a
+	// room-closed message
+	CloseRoom(context.Context, *CloseRoomRequest) (*CloseRoomResponse, error)
+	// RemoveClient disconnects a member of a room with a kicked message
+	RemoveClient(context.Context, *RemoveClientRequest) (*RemoveClientResponse, error)
+	// GetIceServers returns ICE servers with fresh TURN credentials
+	GetIceServers(context.Context, *GetIceServersRequest) (*GetIceServersResponse, error)
+	mustEmbedUnimplementedSignalingServer()
+}
+
+// UnimplementedSignalingServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSignalingServer struct{}
+
+func (UnimplementedSignalingServer) Signal(grpc.BidiStreamingServer[ClientMessage, ServerMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method Signal not implemented")
+}
+func (UnimplementedSignalingServer) ListRooms(context.Context, *ListRoomsRequest) (*ListRoomsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRooms not implemented")
+}
+func (UnimplementedSignalingServer) GetRoom(context.Context, *GetRoomRequest) (*Room, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoom not implemented")
+}
+func (UnimplementedSignalingServer) CloseRoom(context.Context, *CloseRoomRequest) (*CloseRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseRoom not implemented")
+}
+func (UnimplementedSignalingServer) RemoveClient(context.Context, *RemoveClientRequest) (*RemoveClientResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveClient not implemented")
+}
+func (UnimplementedSignalingServer) GetIceServers(context.Context, *GetIceServersRequest) (*GetIceServersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIceServers not implemented")
+}
+func (UnimplementedSignalingServer) mustEmbedUnimplementedSignalingServer() {}
+func (UnimplementedSignalingServer) testEmbeddedByValue()                   {}
+
+// UnsafeSignalingServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SignalingServer will
+// result in compilation errors.
+type UnsafeSignalingServer interface {
+	mustEmbedUnimplementedSignalingServer()
+}
+
+func RegisterSignalingServer(s grpc.ServiceRegistrar, srv SignalingServer) {
+	// If the following call pancis, it indicates UnimplementedSignalingServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Signaling_ServiceDesc, srv)
+}
+
+func _Signaling_Signal_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SignalingServer).Signal(&grpc.GenericServerStream[ClientMessage, ServerMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Signaling_SignalServer = grpc.BidiStreamingServer[ClientMessage, ServerMessage]
+
+func _Signaling_ListRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoomsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).ListRooms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_ListRooms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).ListRooms(ctx, req.(*ListRoomsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_GetRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).GetRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_GetRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).GetRoom(ctx, req.(*GetRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_CloseRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).CloseRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_CloseRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).CloseRoom(ctx, req.(*CloseRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_RemoveClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).RemoveClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_RemoveClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).RemoveClient(ctx, req.(*RemoveClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_GetIceServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIceServersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).GetIceServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_GetIceServers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).GetIceServers(ctx, req.(*GetIceServersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Signaling_ServiceDesc is the grpc.ServiceDesc for Signaling service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Signaling_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "signaling.v1.Signaling",
+	HandlerType: (*SignalingServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRooms",
+			Handler:    _Signaling_ListRooms_Handler,
+		},
+		{
+			MethodName: "GetRoom",
+			Handler:    _Signaling_GetRoom_Handler,
+		},
+		{
+			MethodName: "CloseRoom",
+			Handler:    _Signaling_CloseRoom_Handler,
+		},
+		{
+			MethodName: "RemoveClient",
+			Handler:    _Signaling_RemoveClient_Handler,
+		},
+		{
+			MethodName: "GetIceServers",
+			Handler:    _Signaling_GetIceServers_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Signal",
+			Handler:       _Signaling_Signal_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "signaling.proto",
+}
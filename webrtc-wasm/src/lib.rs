@@ -50,6 +50,7 @@ pub struct WebRTCClient {
     on_status_change: Option<js_sys::Function>,
     on_remote_stream: Option<js_sys::Function>,
     on_ice_candidate: Option<js_sys::Function>,
+    ice_servers: Option<Array>,
 }
 
 #[wasm_bindgen]
@@ -63,6 +64,7 @@ impl WebRTCClient {
             on_status_change: None,
             on_remote_stream: None,
             on_ice_candidate: None,
+            ice_servers: None,
         }
     }
 
@@ -81,6 +83,13 @@ impl WebRTCClient {
         self.on_ice_candidate = Some(callback);
     }
 
+    /// Use the ICE servers issued by the signaling server instead of the built-in defaults
+    #[wasm_bindgen(js_name = setIceServers)]
+    pub fn set_ice_servers(&mut self, servers: Array) {
+        console_log!("[WebRTCClient] Using {} ICE servers from signaling server", servers.length());
+        self.ice_servers = Some(servers);
+    }
+
     fn update_status(&self, message: &str) {
         console_log!("[Status] {}", message);
         if let Some(ref callback) = self.on_status_change {
@@ -119,6 +128,12 @@ impl WebRTCClient {
 
     fn create_rtc_config(&self) -> Result<RtcConfiguration, JsValue> {
         let config = RtcConfiguration::new();
+
+        if let Some(ref servers) = self.ice_servers {
+            config.set_ice_servers(servers);
+            return Ok(config);
+        }
+
         let ice_servers = Array::new();
 
         // STUN servers